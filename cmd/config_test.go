@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestApplyConfig(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "aragomodoro"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "break: 7\nprofiles:\n  deep-work:\n    focus: 50\n    break: 10\n    repeat: 4\n    continue: true\n"
+	if err := os.WriteFile(filepath.Join(configHome, "aragomodoro", "config.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Registering the flags again puts the shared variables back to their
+	// defaults for the other tests.
+	t.Cleanup(func() { addTimerFlags(pflag.NewFlagSet("reset", pflag.ContinueOnError)) })
+
+	tests := []struct {
+		name                        string
+		args                        []string
+		focus, breakMinutes, repeat int
+		continues                   bool
+	}{
+		{"TopLevelOnly", nil, 25, 7, 1, false},
+		{"Profile", []string{"--profile", "deep-work"}, 50, 10, 4, true},
+		{"FlagsWin", []string{"--profile", "deep-work", "-b", "3", "--continue=false"}, 50, 3, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			addTimerFlags(flags)
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := applyConfig(flags); err != nil {
+				t.Fatalf("applyConfig failed: %v", err)
+			}
+			if focusDuration != tt.focus || breakDuration != tt.breakMinutes || repeatCount != tt.repeat || continueOnBreak != tt.continues {
+				t.Errorf("Expected focus %d, break %d, repeat %d, continue %v; got %d, %d, %d, %v",
+					tt.focus, tt.breakMinutes, tt.repeat, tt.continues,
+					focusDuration, breakDuration, repeatCount, continueOnBreak)
+			}
+		})
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addTimerFlags(flags)
+	flags.Parse([]string{"--profile", "nope"})
+	if _, err := applyConfig(flags); err == nil || !strings.Contains(err.Error(), "deep-work") {
+		t.Errorf("Expected an unknown profile error listing deep-work, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/statusbar"
+	"github.com/spf13/cobra"
+)
+
+var ctlCmd = &cobra.Command{
+	Use:   "ctl",
+	Short: "Query or drive a timer running in another terminal",
+}
+
+// newCtlCommand builds the subcommand that sends command to the timer.
+func newCtlCommand(command, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   command,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			status, err := control.Send(control.SocketPath(), command)
+			if err != nil {
+				return err
+			}
+			writeStatus(cmd.OutOrStdout(), status)
+			return nil
+		},
+	}
+}
+
+func writeStatus(w io.Writer, status control.Status) {
+	if status.Phase == "" {
+		fmt.Fprintln(w, "💤 No session has been started.")
+		return
+	}
+
+	fmt.Fprintf(w, "%s %s %d/%d", statusbar.Icon(status.Phase), status.Phase, status.Cycle, status.RepeatCount)
+	if status.Phase != "completed" {
+		remaining := time.Duration(status.Remaining) * time.Second
+		if !status.Active {
+			fmt.Fprintf(w, " · 🛑 stopped with %v left", remaining)
+		} else if status.Paused {
+			fmt.Fprintf(w, " · ⏸️  paused with %v left", remaining)
+		} else {
+			fmt.Fprintf(w, " · ⏳ %v remaining", remaining)
+		}
+	}
+	if status.Task != "" {
+		fmt.Fprintf(w, " · 📝 %s", status.Task)
+	}
+	fmt.Fprintln(w)
+}
+
+func init() {
+	ctlCmd.AddCommand(
+		newCtlCommand(control.CommandStatus, "Show the phase and time left"),
+		newCtlCommand(control.CommandPause, "Pause the countdown"),
+		newCtlCommand(control.CommandResume, "Resume a paused countdown"),
+		newCtlCommand(control.CommandSkip, "End the current phase and move on"),
+		newCtlCommand(control.CommandStop, "Stop the session"),
+	)
+	rootCmd.AddCommand(ctlCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+)
+
+func TestCtlCmdSubcommands(t *testing.T) {
+	for _, name := range []string{"status", "pause", "resume", "skip", "stop"} {
+		if cmd, _, err := ctlCmd.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("Expected subcommand '%s' to exist", name)
+		}
+	}
+}
+
+func TestCtlCmd_NoTimer(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	rootCmd.SetArgs([]string{"ctl", "status"})
+	rootCmd.SetErr(&bytes.Buffer{})
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetErr(nil)
+	}()
+
+	if err := rootCmd.Execute(); !errors.Is(err, control.ErrNotRunning) {
+		t.Errorf("Expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestWriteStatus(t *testing.T) {
+	tests := []struct {
+		status   control.Status
+		expected string
+	}{
+		{control.Status{Active: true, Phase: "focus", Cycle: 1, RepeatCount: 4, Remaining: 750, Task: "write RFC"}, "🧭 focus 1/4 · ⏳ 12m30s remaining · 📝 write RFC\n"},
+		{control.Status{Active: true, Phase: "break", Cycle: 2, RepeatCount: 4, Remaining: 60, Paused: true}, "🌿 break 2/4 · ⏸️  paused with 1m0s left\n"},
+		{control.Status{Phase: "completed", Cycle: 4, RepeatCount: 4}, "🎉 completed 4/4\n"},
+		{control.Status{Phase: "focus", Cycle: 1, RepeatCount: 1, Remaining: 30}, "🧭 focus 1/1 · 🛑 stopped with 30s left\n"},
+		{control.Status{}, "💤 No session has been started.\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		writeStatus(&out, tt.status)
+		if out.String() != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/config"
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/aureliomalheiros/aragomodoro/internal/web"
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+	"github.com/spf13/cobra"
+)
+
+var daemonDetach bool
+
+// daemonStartTimeout bounds how long a detached daemon gets to answer.
+const daemonStartTimeout = 5 * time.Second
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the timer in the background, driven by start, stop and status",
+	Long: "The daemon owns the timer, its sounds and the history, so a session keeps going after the terminal that started it is closed. " +
+		"`aragomodoro start`, `stop`, `status` and `ctl` talk to it over its socket, and with --web it also serves the web interface on the same timer.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if daemonDetach {
+			return detachDaemon()
+		}
+
+		path, err := config.DefaultPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return runDaemon(ctx, cfg)
+	},
+}
+
+var startCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start a session on the daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if _, err := applyConfig(cmd.Flags()); err != nil {
+			return err
+		}
+
+		status, err := control.Do(control.SocketPath(), control.Request{
+			Command: control.CommandStart,
+			Session: &control.Session{
+				Focus:          focusDuration,
+				Break:          breakDuration,
+				LongBreak:      longBreakDuration,
+				LongBreakEvery: longBreakEvery,
+				Repeat:         repeatCount,
+				Continue:       continueOnBreak,
+				Task:           taskLabel,
+			},
+		})
+		if errors.Is(err, control.ErrNotRunning) {
+			return errors.New("no daemon is running, start one with: aragomodoro daemon --detach")
+		}
+		if err != nil {
+			return err
+		}
+		writeStatus(cmd.OutOrStdout(), status)
+		return nil
+	},
+}
+
+// runDaemon serves the control socket, and the web interface with --web,
+// until ctx is done, then stops the session.
+func runDaemon(ctx context.Context, cfg *config.Config) error {
+	server := web.NewServer(webPort)
+	server.SetHistory(openHistory())
+	server.SetConfig(cfg)
+	cues, err := soundCues(sound.WebCues())
+	if err != nil {
+		return err
+	}
+	server.SetSounds(cues)
+	finishSound, err := useSoundOutput()
+	if err != nil {
+		return err
+	}
+	defer finishSound()
+	if taskStore, err := openTasks(); err == nil {
+		server.SetTasks(taskStore)
+	} else {
+		fmt.Printf("⚠️  Task list not available: %v\n", err)
+	}
+
+	handler := web.ControlHandler()
+	socketPath := control.SocketPath()
+	socket, err := control.Listen(socketPath, handler)
+	if err != nil {
+		return err
+	}
+	defer socket.Close()
+	fmt.Printf("🛡️  Aragomodoro daemon listening on %s\n", socketPath)
+
+	if webMode {
+		fmt.Printf("Access at: http://localhost:%d\n", webPort)
+		return server.Run(ctx)
+	}
+	<-ctx.Done()
+	server.StopSession()
+	return nil
+}
+
+// detachDaemon starts the daemon again in a session of its own, logging to
+// a file, and waits until it answers on its socket.
+func detachDaemon() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"daemon", "--port", strconv.Itoa(webPort)}
+	if webMode {
+		args = append(args, "--web")
+	}
+	if focusSound != "" {
+		args = append(args, "--focus-sound", focusSound)
+	}
+	if breakSound != "" {
+		args = append(args, "--break-sound", breakSound)
+	}
+	if soundOutput != "speaker" {
+		args = append(args, "--sound-output", soundOutput)
+	}
+
+	logPath := filepath.Join(xdg.RuntimeDir(), "daemon.log")
+	if err := xdg.MkdirPrivate(filepath.Dir(logPath)); err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	daemon := exec.Command(executable, args...)
+	daemon.Stdout = logFile
+	daemon.Stderr = logFile
+	daemon.SysProcAttr = detachedProcAttr()
+	if err := daemon.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- daemon.Wait()
+	}()
+	for deadline := time.Now().Add(daemonStartTimeout); time.Now().Before(deadline); {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("daemon exited (%v), see %s", err, logPath)
+		case <-time.After(50 * time.Millisecond):
+		}
+		if _, err := control.Send(control.SocketPath(), control.CommandStatus); err == nil {
+			fmt.Printf("🛡️  Daemon running with pid %d, logging to %s\n", daemon.Process.Pid, logPath)
+			return nil
+		}
+	}
+	return fmt.Errorf("daemon did not answer within %v, see %s", daemonStartTimeout, logPath)
+}
+
+func init() {
+	daemonFlags := daemonCmd.Flags()
+	daemonFlags.BoolVarP(&daemonDetach, "detach", "d", false, "Run in the background and return once the daemon is up")
+	daemonFlags.BoolVarP(&webMode, "web", "w", false, "Also serve the web interface")
+	daemonFlags.IntVarP(&webPort, "port", "p", 8080, "Port for the web server")
+	addSoundFlags(daemonFlags)
+
+	addTimerFlags(startCmd.Flags())
+	startCmd.Flags().StringVarP(&taskLabel, "task", "t", "", "What this session is for")
+
+	rootCmd.AddCommand(
+		daemonCmd,
+		startCmd,
+		newCtlCommand(control.CommandStop, "Stop the daemon's session"),
+	)
+}
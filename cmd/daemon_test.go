@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/config"
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+func TestDaemonCmdFlags(t *testing.T) {
+	for _, flagName := range []string{"detach", "web", "port"} {
+		if daemonCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Expected daemon flag '%s' to exist", flagName)
+		}
+	}
+	for _, flagName := range []string{"focus", "break", "repeat", "profile", "task"} {
+		if startCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Expected start flag '%s' to exist", flagName)
+		}
+	}
+	for _, name := range []string{"daemon", "start", "stop", "status"} {
+		if cmd, _, err := rootCmd.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("Expected command '%s' to exist", name)
+		}
+	}
+}
+
+func TestRunDaemon(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runDaemon(ctx, &config.Config{})
+	}()
+
+	path := control.SocketPath()
+	var status control.Status
+	var err error
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if status, err = control.Send(path, control.CommandStatus); err == nil {
+			break
+		}
+	}
+	if err != nil || status.Phase != "" {
+		t.Fatalf("Expected an idle daemon, got %+v, %v", status, err)
+	}
+
+	status, err = control.Do(path, control.Request{
+		Command: control.CommandStart,
+		Session: &control.Session{Focus: 25, Break: 5, Repeat: 1, Task: "write RFC"},
+	})
+	if err != nil || !status.Active || status.Phase != "focus" || status.Task != "write RFC" {
+		t.Fatalf("Expected a running session, got %+v, %v", status, err)
+	}
+	if status, err = control.Send(path, control.CommandStop); err != nil || status != (control.Status{}) {
+		t.Errorf("Expected the daemon idle after stop, got %+v, %v", status, err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Daemon returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Daemon did not shut down")
+	}
+	if _, err := control.Send(path, control.CommandStatus); err != control.ErrNotRunning {
+		t.Errorf("Expected the socket gone after shutdown, got %v", err)
+	}
+}
+
+func TestRunDaemon_RecordsStoppedPhase(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runDaemon(ctx, &config.Config{})
+	}()
+
+	path := control.SocketPath()
+	var err error
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err = control.Do(path, control.Request{
+			Command: control.CommandStart,
+			Session: &control.Session{Focus: 25, Break: 5, Repeat: 1},
+		}); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("Expected the daemon to start a session, got %v", err)
+	}
+
+	// Shutting down with a session running stops it, and the stopped
+	// phase is in the history by the time the daemon returns.
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Daemon returned %v", err)
+	}
+	historyPath, err := history.DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := history.Open(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Outcome != history.OutcomeStopped {
+		t.Errorf("Expected the stopped focus phase in the history, got %+v", entries)
+	}
+}
@@ -1,29 +1,63 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/aureliomalheiros/aragomodoro/internal/ascii_text"
+	"github.com/aureliomalheiros/aragomodoro/internal/config"
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
 	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/aureliomalheiros/aragomodoro/internal/todotxt"
 	"github.com/aureliomalheiros/aragomodoro/internal/web"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	focusDuration   int
-	breakDuration   int
-	repeatCount     int
-	continueOnBreak bool
-	webMode         bool
-	webPort         int
+	focusDuration     int
+	breakDuration     int
+	longBreakDuration int
+	longBreakEvery    int
+	repeatCount       int
+	continueOnBreak   bool
+	webMode           bool
+	webPort           int
+	profileName       string
+	taskLabel         string
+	taskFrom          string
+	fontName          string
+	focusSound        string
+	breakSound        string
+	soundOutput       string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "aragomodoro",
 	Short: "Aragomodoro: A playful Pomodoro timer inspired by Aragorn",
 	Long:  "Aragomodoro is a playful take on the Pomodoro technique, inspired by the spirit of Aragorn from The Lord of the Rings.",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Flags parsed fine by now; usage would only bury a runtime error.
+		cmd.SilenceUsage = true
+
+		cfg, err := applyConfig(cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		store := openHistory()
+
 		if webMode {
 			var port int = webPort
 			if port == 0 {
@@ -33,16 +67,175 @@ var rootCmd = &cobra.Command{
 			fmt.Printf("Access at: http://localhost:%d\n", port)
 
 			webServer := web.NewServer(port)
-			if err := webServer.Start(); err != nil {
-				panic(err)
+			webServer.SetHistory(store)
+			webServer.SetConfig(cfg)
+			cues, err := soundCues(sound.WebCues())
+			if err != nil {
+				return err
 			}
-		} else {
-			ascii_text.PrintAsciiTextAragomodoro()
-			pomodoro.PomodoroTimer(focusDuration, breakDuration, repeatCount, continueOnBreak)
+			webServer.SetSounds(cues)
+			finishSound, err := useSoundOutput()
+			if err != nil {
+				return err
+			}
+			defer finishSound()
+			if taskStore, err := openTasks(); err == nil {
+				webServer.SetTasks(taskStore)
+			} else {
+				fmt.Printf("⚠️  Task list not available: %v\n", err)
+			}
+			// status and ctl reach the page's timer through the socket.
+			if socket, err := control.Listen(control.SocketPath(), web.ControlHandler()); err == nil {
+				defer socket.Close()
+			} else {
+				fmt.Printf("⚠️  aragomodoro status will not reach this timer: %v\n", err)
+			}
+			return webServer.Run(ctx)
 		}
+
+		if taskFrom != "" {
+			line, err := openTodoLine(taskFrom)
+			if err != nil {
+				return err
+			}
+			return runSession(ctx, phaseRecorder(store, line.AddPomodoro), line.Item.Label())
+		}
+		return runSession(ctx, phaseRecorder(store, nil), taskLabel)
 	},
 }
 
+// runSession runs a terminal session with the timer, font and sound flags,
+// controllable through `aragomodoro ctl`, and prints a summary when it is
+// interrupted or stopped.
+func runSession(ctx context.Context, record func(pomodoro.PhaseRecord) error, task string) error {
+	font, err := ascii_text.LoadFont(fontName)
+	if err != nil {
+		return err
+	}
+	cues, err := soundCues(sound.TerminalCues())
+	if err != nil {
+		return err
+	}
+	finishSound, err := useSoundOutput()
+	if err != nil {
+		return err
+	}
+	defer finishSound()
+
+	ascii_text.PrintBanner(font)
+	summary, err := pomodoro.PomodoroTimer(ctx, pomodoro.Session{
+		FocusDuration:     focusDuration,
+		BreakDuration:     breakDuration,
+		LongBreakDuration: longBreakDuration,
+		LongBreakEvery:    longBreakEvery,
+		RepeatCount:       repeatCount,
+		ContinueOnBreak:   continueOnBreak,
+		Task:              task,
+		Font:              font,
+		Cues:              cues,
+		Record:            record,
+		Attach:            listenControl,
+	})
+	if errors.Is(err, context.Canceled) || errors.Is(err, pomodoro.ErrStopped) {
+		pomodoro.PrintSummary(summary)
+		return nil
+	}
+	return err
+}
+
+// listenControl serves ctl requests for the session's timer, carrying on
+// without them when the socket cannot be set up.
+func listenControl(timer *pomodoro.Timer) func() {
+	server, err := control.Listen(control.SocketPath(), control.TimerHandler(timer))
+	if err != nil {
+		fmt.Printf("⚠️  aragomodoro ctl will not reach this timer: %v\n", err)
+		return func() {}
+	}
+	return func() {
+		server.Close()
+	}
+}
+
+// phaseRecorder saves every phase in the history, which may be nil, and
+// calls countPomodoro, when set, for every completed pomodoro.
+func phaseRecorder(historyStore *history.Store, countPomodoro func() error) func(pomodoro.PhaseRecord) error {
+	return func(record pomodoro.PhaseRecord) error {
+		var errs []error
+		if err := historyStore.Append(record.HistoryEntry()); err != nil {
+			errs = append(errs, fmt.Errorf("could not save history: %w", err))
+		}
+		if countPomodoro != nil && record.CompletedPomodoro() {
+			if err := countPomodoro(); err != nil {
+				errs = append(errs, fmt.Errorf("could not count the pomodoro: %w", err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// openTodoLine opens the todo.txt line a --task-from reference points
+// at, which has to be an open task.
+func openTodoLine(ref string) (*todotxt.Line, error) {
+	path, number, err := todotxt.ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	line, err := todotxt.Open(path, number)
+	if err != nil {
+		return nil, err
+	}
+	if line.Item.Done {
+		return nil, fmt.Errorf("%s is already done", ref)
+	}
+	return line, nil
+}
+
+// applyConfig loads the config file and fills in every timer flag that was
+// not given on the command line from it and the selected profile.
+func applyConfig(flags *pflag.FlagSet) (*config.Config, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := cfg.Resolve(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	setInt := func(name string, target *int, value *int) {
+		if value != nil && !flags.Changed(name) {
+			*target = *value
+		}
+	}
+	setInt("focus", &focusDuration, settings.Focus)
+	setInt("break", &breakDuration, settings.Break)
+	setInt("long-break", &longBreakDuration, settings.LongBreak)
+	setInt("long-break-every", &longBreakEvery, settings.LongBreakEvery)
+	setInt("repeat", &repeatCount, settings.Repeat)
+	if settings.Continue != nil && !flags.Changed("continue") {
+		continueOnBreak = *settings.Continue
+	}
+	return cfg, nil
+}
+
+// openHistory opens the history store, carrying on without one when it is
+// not available so a timer can always be run.
+func openHistory() *history.Store {
+	path, err := history.DefaultPath()
+	if err == nil {
+		var store *history.Store
+		if store, err = history.Open(path); err == nil {
+			return store
+		}
+	}
+	fmt.Printf("⚠️  History will not be saved: %v\n", err)
+	return nil
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -50,10 +243,111 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.Flags().IntVarP(&focusDuration, "focus", "f", 25, "Focus duration in minutes")
-	rootCmd.Flags().IntVarP(&breakDuration, "break", "b", 5, "Break duration in minutes")
-	rootCmd.Flags().IntVarP(&repeatCount, "repeat", "r", 1, "Number of Pomodoros before a long break")
-	rootCmd.Flags().BoolVarP(&continueOnBreak, "continue", "c", false, "Continue the timer during breaks")
-	rootCmd.Flags().BoolVarP(&webMode, "web", "w", false, "Start the web interface")
-	rootCmd.Flags().IntVarP(&webPort, "port", "p", 8080, "Port for the web server")
+	flags := rootCmd.Flags()
+	addTimerFlags(flags)
+	flags.BoolVarP(&webMode, "web", "w", false, "Start the web interface")
+	flags.IntVarP(&webPort, "port", "p", 8080, "Port for the web server")
+	flags.StringVarP(&taskLabel, "task", "t", "", "What this session is for, shown in the countdown and saved in the history")
+	flags.StringVar(&taskFrom, "task-from", "", "Run the session for a todo.txt line (file:line), counting pomodoros in it")
+	rootCmd.MarkFlagsMutuallyExclusive("task", "task-from")
+	addFontFlag(flags)
+	addSoundFlags(flags)
+	rootCmd.MarkFlagsMutuallyExclusive("web", "task")
+	rootCmd.MarkFlagsMutuallyExclusive("web", "task-from")
+	rootCmd.MarkFlagsMutuallyExclusive("web", "font")
+}
+
+// addFontFlag registers --font for commands that draw a terminal session.
+func addFontFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&fontName, "font", "block", "Font for the banner and countdown: "+strings.Join(ascii_text.Fonts, ", ")+", or a FIGlet .flf file")
+}
+
+// addSoundFlags registers the flags that pick the themes played as phases
+// end.
+func addSoundFlags(flags *pflag.FlagSet) {
+	themes := strings.Join(sound.Builtins().Names(), ", ")
+	flags.StringVar(&focusSound, "focus-sound", "", "Sound theme played when a focus phase ends ("+themes+", one in the themes directory, or an audio or theme file)")
+	flags.StringVar(&breakSound, "break-sound", "", "Sound theme or file played when a break or long break ends")
+	flags.StringVar(&soundOutput, "sound-output", "speaker", "Where sounds play: speaker, none, or a .wav file to record them into")
+}
+
+// useSoundOutput makes sounds play where --sound-output says. The returned
+// func finishes the output, writing a recording to its file.
+func useSoundOutput() (func(), error) {
+	output, err := sound.ParseOutput(soundOutput)
+	if err != nil {
+		return nil, err
+	}
+	sound.SetOutput(output)
+	return func() {
+		if file, ok := output.(*sound.WAVFile); ok {
+			if err := file.Close(); err != nil {
+				fmt.Printf("⚠️  Sounds were not recorded: %v\n", err)
+			}
+		}
+	}, nil
+}
+
+// soundCues swaps the themes named by --focus-sound and --break-sound
+// into defaults. Themes are the built-ins and the files in the themes
+// directory, whose broken files only get a warning, or a file given by its
+// path.
+func soundCues(defaults sound.Cues) (sound.Cues, error) {
+	if focusSound == "" && breakSound == "" {
+		return defaults, nil
+	}
+	themes, err := loadThemes()
+	if err != nil {
+		return defaults, err
+	}
+
+	cues := defaults
+	if focusSound != "" {
+		if cues.Focus, err = findTheme(themes, focusSound); err != nil {
+			return defaults, err
+		}
+	}
+	if breakSound != "" {
+		theme, err := findTheme(themes, breakSound)
+		if err != nil {
+			return defaults, err
+		}
+		cues.Break, cues.LongBreak = theme, theme
+	}
+	return cues, nil
+}
+
+// loadThemes returns the built-in themes and those in the themes
+// directory, warning about the files that cannot be read.
+func loadThemes() (*sound.Registry, error) {
+	dir, err := sound.DefaultThemeDir()
+	if err != nil {
+		return nil, err
+	}
+	themes, err := sound.LoadThemes(dir)
+	if err != nil {
+		fmt.Printf("⚠️  Some sound themes were left out: %v\n", err)
+	}
+	return themes, nil
+}
+
+// findTheme returns the theme called name, or reads it from a file when
+// name is a path.
+func findTheme(themes *sound.Registry, name string) (sound.Theme, error) {
+	if filepath.Ext(name) != "" || strings.ContainsRune(name, filepath.Separator) {
+		return sound.LoadFile(name)
+	}
+	return themes.Get(name)
+}
+
+// addTimerFlags registers the flags that shape a session, shared by every
+// command that runs one.
+func addTimerFlags(flags *pflag.FlagSet) {
+	flags.IntVarP(&focusDuration, "focus", "f", config.DefaultFocus, "Focus duration in minutes")
+	flags.IntVarP(&breakDuration, "break", "b", config.DefaultBreak, "Break duration in minutes")
+	flags.IntVar(&longBreakDuration, "long-break", config.DefaultLongBreak, "Long break duration in minutes")
+	flags.IntVar(&longBreakEvery, "long-break-every", config.DefaultLongBreakEvery, "Number of Pomodoros before a long break (0 disables long breaks)")
+	flags.IntVarP(&repeatCount, "repeat", "r", config.DefaultRepeat, "Number of Pomodoros to run")
+	flags.BoolVarP(&continueOnBreak, "continue", "c", false, "Continue the timer during breaks")
+	flags.StringVar(&profileName, "profile", "", "Profile from the config file to take defaults from")
 }
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/spf13/pflag"
 )
 
 func TestRootCmd(t *testing.T) {
@@ -26,10 +31,18 @@ func TestRootCmdFlags(t *testing.T) {
 	expectedFlags := []string{
 		"focus",
 		"break",
+		"long-break",
+		"long-break-every",
 		"repeat",
 		"continue",
 		"web",
 		"port",
+		"task",
+		"profile",
+		"font",
+		"focus-sound",
+		"break-sound",
+		"sound-output",
 	}
 
 	for _, flagName := range expectedFlags {
@@ -55,10 +68,13 @@ func TestRootCmdFlagDefaults(t *testing.T) {
 	}{
 		{"focus", "25"},
 		{"break", "5"},
+		{"long-break", "15"},
+		{"long-break-every", "4"},
 		{"repeat", "1"},
 		{"continue", "false"},
 		{"web", "false"},
 		{"port", "8080"},
+		{"font", "block"},
 	}
 
 	for _, tt := range tests {
@@ -74,6 +90,38 @@ func TestRootCmdFlagDefaults(t *testing.T) {
 	}
 }
 
+// resetRootFlags puts every root flag back to its default, so a test can
+// run rootCmd no matter which flags earlier runs set, --help included.
+func resetRootFlags() {
+	rootCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	})
+}
+
+// The web interface picks its task on the page, so the terminal session
+// flags are refused with --web rather than ignored.
+func TestRootCmd_WebRefusesSessionFlags(t *testing.T) {
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetErr(nil)
+		resetRootFlags()
+	}()
+	rootCmd.SetErr(io.Discard)
+
+	for _, args := range [][]string{
+		{"--web", "--task", "write RFC"},
+		{"--web", "--task-from", "todo.txt:1"},
+		{"--web", "--font", "mini"},
+	} {
+		resetRootFlags()
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "none of the others can be") {
+			t.Errorf("%v: expected the flags refused together, got %v", args, err)
+		}
+	}
+}
+
 func TestRootCmdHelp(t *testing.T) {
 	// Capture output
 	oldStdout := os.Stdout
@@ -141,6 +189,11 @@ func TestFlagValidation(t *testing.T) {
 			args:     []string{"--focus", "25", "--break", "5", "--repeat", "1"},
 			hasError: false,
 		},
+		{
+			name:     "LongBreak",
+			args:     []string{"--repeat", "4", "--long-break", "20", "--long-break-every", "2"},
+			hasError: false,
+		},
 		{
 			name:     "WebMode",
 			args:     []string{"--web", "--port", "8080"},
@@ -181,3 +234,69 @@ func BenchmarkRootCmdParsing(b *testing.B) {
 		cmd.ParseFlags(args)
 	}
 }
+
+func TestSoundCues(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	themes := filepath.Join(configHome, "aragomodoro", "themes")
+	if err := os.MkdirAll(themes, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(themes, "shire.yaml"), []byte("notes: [G4 100ms]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { focusSound, breakSound = "", "" })
+
+	defaults := sound.TerminalCues()
+	if cues, err := soundCues(defaults); err != nil || cues.Focus.Name != "aragorn" || cues.LongBreak.Name != "minas-tirith" {
+		t.Errorf("soundCues without flags = %s, %s, %v, want the defaults", cues.Focus.Name, cues.LongBreak.Name, err)
+	}
+
+	focusSound, breakSound = "shire", "elves"
+	cues, err := soundCues(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cues.Focus.Name != "shire" || cues.Break.Name != "elves" || cues.LongBreak.Name != "elves" {
+		t.Errorf("soundCues = %s, %s, %s", cues.Focus.Name, cues.Break.Name, cues.LongBreak.Name)
+	}
+
+	focusSound = "nazgul"
+	if _, err := soundCues(defaults); err == nil || !strings.Contains(err.Error(), "nazgul") {
+		t.Errorf("soundCues error = %v, want an unknown theme", err)
+	}
+
+	focusSound = filepath.Join(configHome, "horn.wav")
+	if _, err := soundCues(defaults); err == nil || !strings.Contains(err.Error(), "horn.wav") {
+		t.Errorf("soundCues error = %v, want the missing file named", err)
+	}
+}
+
+func TestUseSoundOutput(t *testing.T) {
+	defer func() {
+		soundOutput = "speaker"
+		sound.SetOutput(sound.Speaker{})
+	}()
+
+	soundOutput = "bluetooth"
+	if _, err := useSoundOutput(); err == nil || !strings.Contains(err.Error(), "bluetooth") {
+		t.Errorf("useSoundOutput error = %v, want an unknown output", err)
+	}
+
+	soundOutput = filepath.Join(t.TempDir(), "session.wav")
+	finish, err := useSoundOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sound.TerminalCues().Focus.Play(); err != nil {
+		t.Fatal(err)
+	}
+	finish()
+	recording, err := sound.LoadClip(soundOutput, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := sound.TerminalCues().Focus.Duration(); recording.Duration() < want-time.Millisecond {
+		t.Errorf("recording lasts %s, want the focus cue, %s", recording.Duration(), want)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderTheme  string
+	renderOutput string
+)
+
+var soundCmd = &cobra.Command{
+	Use:   "sound",
+	Short: "Work with the sound themes played as phases end",
+}
+
+var soundRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Write a sound theme to a WAV file instead of playing it",
+	Long:  "Renders a built-in theme, one in the themes directory or a theme file, exactly as it would play, so it can be previewed on a machine without audio.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		themes, err := loadThemes()
+		if err != nil {
+			return err
+		}
+		theme, err := findTheme(themes, renderTheme)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		output := renderOutput
+		if output == "" {
+			output = theme.Name + ".wav"
+		}
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		if err := theme.Render(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "🎵 Rendered %s (%s) to %s\n", theme.Name, theme.Duration().Round(time.Millisecond), output)
+		return nil
+	},
+}
+
+func init() {
+	soundRenderCmd.Flags().StringVar(&renderTheme, "theme", "", "Theme to render, by name or as a theme or audio file")
+	soundRenderCmd.Flags().StringVarP(&renderOutput, "output", "o", "", "WAV file to write, <theme>.wav when left out")
+	soundRenderCmd.MarkFlagRequired("theme")
+	soundCmd.AddCommand(soundRenderCmd)
+	rootCmd.AddCommand(soundCmd)
+}
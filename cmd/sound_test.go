@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSoundRenderCmd(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	output := filepath.Join(t.TempDir(), "aragorn.wav")
+	var out bytes.Buffer
+	rootCmd.SetArgs([]string{"sound", "render", "--theme", "aragorn", "-o", output})
+	rootCmd.SetOut(&out)
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		renderTheme, renderOutput = "", ""
+	}()
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Rendered aragorn") {
+		t.Errorf("output = %q", out.String())
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("RIFF")) || len(data) < 44100 {
+		t.Errorf("%s is not a WAV file of the theme, %d bytes", output, len(data))
+	}
+}
+
+func TestSoundRenderCmd_UnknownTheme(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	rootCmd.SetArgs([]string{"sound", "render", "--theme", "nazgul", "-o", filepath.Join(t.TempDir(), "x.wav")})
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		renderTheme, renderOutput = "", ""
+	}()
+
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "nazgul") {
+		t.Errorf("Execute() error = %v, want an unknown theme", err)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+	"github.com/aureliomalheiros/aragomodoro/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsFrom   string
+	statsTo     string
+	statsFormat string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show focus time, pomodoros and streaks from the session history",
+	Long:  "Reports on today and this week by default, or on the days between --from and --to (inclusive) when either is given.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		periods, err := statsPeriods(now)
+		if err != nil {
+			return err
+		}
+		if err := stats.ValidateFormat(statsFormat); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		path, err := history.DefaultPath()
+		if err != nil {
+			return err
+		}
+		entries, err := history.Read(path)
+		if err != nil {
+			return err
+		}
+
+		reports := make([]stats.Report, 0, len(periods))
+		for _, period := range periods {
+			reports = append(reports, stats.Build(entries, period, now))
+		}
+		return stats.Write(cmd.OutOrStdout(), statsFormat, reports)
+	},
+}
+
+// statsPeriods turns the flags into the periods to report on. A range with
+// only --from runs to today and one with only --to starts that same day.
+func statsPeriods(now time.Time) ([]stats.Period, error) {
+	if statsFrom == "" && statsTo == "" {
+		return []stats.Period{stats.Today(now), stats.ThisWeek(now)}, nil
+	}
+
+	from, to := now, now
+	var err error
+	if statsTo != "" {
+		if to, err = time.ParseInLocation("2006-01-02", statsTo, now.Location()); err != nil {
+			return nil, fmt.Errorf("invalid --to date %q, expected YYYY-MM-DD", statsTo)
+		}
+		from = to
+	}
+	if statsFrom != "" {
+		if from, err = time.ParseInLocation("2006-01-02", statsFrom, now.Location()); err != nil {
+			return nil, fmt.Errorf("invalid --from date %q, expected YYYY-MM-DD", statsFrom)
+		}
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("--from %s is after --to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	return []stats.Period{stats.Range(from, to)}, nil
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsFrom, "from", "", "First day of a custom range (YYYY-MM-DD)")
+	statsCmd.Flags().StringVar(&statsTo, "to", "", "Last day of a custom range (YYYY-MM-DD), defaults to today")
+	statsCmd.Flags().StringVar(&statsFormat, "format", stats.FormatTable, "Output format: table, json or csv")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+func TestStatsCmdFlags(t *testing.T) {
+	for _, flagName := range []string{"from", "to", "format"} {
+		if statsCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Expected flag '%s' to exist", flagName)
+		}
+	}
+}
+
+func TestStatsPeriods(t *testing.T) {
+	now := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to string
+		expected []string
+		hasError bool
+	}{
+		{name: "Default", expected: []string{"2024-01-10..2024-01-10", "2024-01-08..2024-01-14"}},
+		{name: "Range", from: "2024-01-01", to: "2024-01-05", expected: []string{"2024-01-01..2024-01-05"}},
+		{name: "FromOnly", from: "2024-01-01", expected: []string{"2024-01-01..2024-01-10"}},
+		{name: "ToOnly", to: "2024-01-05", expected: []string{"2024-01-05..2024-01-05"}},
+		{name: "Reversed", from: "2024-01-05", to: "2024-01-01", hasError: true},
+		{name: "BadDate", from: "01/01/2024", hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statsFrom, statsTo = tt.from, tt.to
+			defer func() { statsFrom, statsTo = "", "" }()
+
+			periods, err := statsPeriods(now)
+			if tt.hasError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(periods) != len(tt.expected) {
+				t.Fatalf("Expected %d periods, got %d", len(tt.expected), len(periods))
+			}
+			for i, period := range periods {
+				got := period.From.Format("2006-01-02") + ".." + period.LastDay().Format("2006-01-02")
+				if got != tt.expected[i] {
+					t.Errorf("Period %d: expected %s, got %s", i, tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsCmd_ReadsHistory(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataDir)
+	store, err := history.Open(filepath.Join(dataDir, "aragomodoro", "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 3, 9, 0, 0, 0, time.Local)
+	store.Append(history.Entry{Start: start, End: start.Add(25 * time.Minute), Type: "focus", Outcome: history.OutcomeCompleted, Planned: 25 * time.Minute, Actual: 25 * time.Minute})
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"stats", "--from", "2024-01-01", "--to", "2024-01-07", "--format", "json"})
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		statsFrom, statsTo, statsFormat = "", "", "table"
+	}()
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+
+	var reports []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &reports); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out.String(), err)
+	}
+	if len(reports) != 1 || reports[0]["completedPomodoros"] != 1.0 || reports[0]["focusSeconds"] != 1500.0 {
+		t.Errorf("Unexpected report %v", reports)
+	}
+}
+
+func TestStatsCmd_LeavesDataDirAlone(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataDir)
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+		statsFormat = "table"
+	}()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+
+	rootCmd.SetArgs([]string{"stats", "--format", "xml"})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), `unknown format "xml"`) {
+		t.Errorf("Expected the format refused, got %v", err)
+	}
+	rootCmd.SetArgs([]string{"stats", "--format", "table"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("stats without a history failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "aragomodoro")); !os.IsNotExist(err) {
+		t.Errorf("stats should not create the data directory, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/statusbar"
+	"github.com/spf13/cobra"
+)
+
+const statusFormatText = "text"
+
+// statusWatchInterval is how often --watch polls the timer.
+const statusWatchInterval = time.Second
+
+var (
+	statusFormat string
+	statusWatch  bool
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running session, also in status bar formats",
+	Long: "Shows the session of the daemon, a terminal timer or the web interface. " +
+		"The tmux, waybar-json, i3bar and plain formats print one line for a status bar, which is empty while no session runs; " +
+		"--watch keeps printing a line whenever it changes.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formats := append([]string{statusFormatText}, statusbar.Formats...)
+		if !slices.Contains(formats, statusFormat) {
+			return fmt.Errorf("unknown format %q, expected one of %s", statusFormat, strings.Join(formats, ", "))
+		}
+		if statusWatch && (statusFormat == statusFormatText || statusFormat == statusbar.FormatTmux) {
+			return fmt.Errorf("--watch streams the plain, %s and %s formats; tmux polls on its own", statusbar.FormatWaybar, statusbar.FormatI3bar)
+		}
+		cmd.SilenceUsage = true
+		path := control.SocketPath()
+
+		if statusFormat == statusFormatText {
+			status, err := control.Send(path, control.CommandStatus)
+			if err != nil {
+				return err
+			}
+			writeStatus(cmd.OutOrStdout(), status)
+			return nil
+		}
+
+		if statusWatch {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return statusbar.Watch(ctx, cmd.OutOrStdout(), statusFormat, statusWatchInterval, func() control.Status {
+				status, _ := barStatus(path)
+				return status
+			})
+		}
+
+		status, err := barStatus(path)
+		if err != nil {
+			return err
+		}
+		line, err := statusbar.Line(statusFormat, status)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+		return nil
+	},
+}
+
+// barStatus asks for the session's status, taking a timer that is not
+// running as an idle status, which is what a status bar should show.
+func barStatus(path string) (control.Status, error) {
+	status, err := control.Send(path, control.CommandStatus)
+	if errors.Is(err, control.ErrNotRunning) {
+		return control.Status{}, nil
+	}
+	return status, err
+}
+
+func init() {
+	statusCmd.Flags().StringVar(&statusFormat, "format", statusFormatText, "Output format: text, plain, tmux, waybar-json or i3bar")
+	statusCmd.Flags().BoolVar(&statusWatch, "watch", false, "Keep printing the status as it changes")
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStatusCmdFlags(t *testing.T) {
+	for _, flagName := range []string{"format", "watch"} {
+		if statusCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Expected flag '%s' to exist", flagName)
+		}
+	}
+}
+
+func TestStatusCmd(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		wantErr  bool
+	}{
+		{"WaybarIdle", []string{"status", "--format", "waybar-json"}, `{"text":"","class":"idle","alt":"idle","percentage":0}` + "\n", false},
+		{"TmuxIdle", []string{"status", "--format", "tmux"}, "\n", false},
+		{"TextNoTimer", []string{"status"}, "", true},
+		{"UnknownFormat", []string{"status", "--format", "lemonbar"}, "", true},
+		{"WatchTmux", []string{"status", "--format", "tmux", "--watch"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(tt.args)
+			defer func() {
+				rootCmd.SetOut(nil)
+				rootCmd.SetErr(nil)
+				rootCmd.SetArgs(nil)
+				statusFormat, statusWatch = statusFormatText, false
+			}()
+
+			err := rootCmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && out.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
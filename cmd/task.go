@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/tasks"
+	"github.com/aureliomalheiros/aragomodoro/internal/todotxt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	taskEstimate int
+	taskListAll  bool
+)
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Keep a task list and spend pomodoros on it",
+}
+
+var taskAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Short: "Add a task",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openTasks()
+		if err != nil {
+			return err
+		}
+		task, err := store.Add(strings.Join(args, " "), taskEstimate)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "➕ Added task #%d: %s (%s)\n", task.ID, task.Title, pomodoroCount(task))
+		return nil
+	},
+}
+
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List open tasks, or every task with --all",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openTasks()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		return writeTasks(cmd.OutOrStdout(), list, taskListAll)
+	},
+}
+
+var taskDoneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Short: "Mark a task as done",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+		store, err := openTasks()
+		if err != nil {
+			return err
+		}
+		task, err := store.SetDone(id, true)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✅ Done: #%d %s (%s)\n", task.ID, task.Title, pomodoroCount(task))
+		return nil
+	},
+}
+
+var taskEstimateCmd = &cobra.Command{
+	Use:   "estimate <id> <pomodoros>",
+	Short: "Change how many pomodoros a task should take",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+		estimate, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid estimate %q", args[1])
+		}
+		store, err := openTasks()
+		if err != nil {
+			return err
+		}
+		task, err := store.SetEstimate(id, estimate)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "🎯 #%d %s: %s\n", task.ID, task.Title, pomodoroCount(task))
+		return nil
+	},
+}
+
+var taskStartCmd = &cobra.Command{
+	Use:   "start <id>",
+	Short: "Run a session for a task, counting its completed pomodoros",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		if _, err := applyConfig(cmd.Flags()); err != nil {
+			return err
+		}
+		store, err := openTasks()
+		if err != nil {
+			return err
+		}
+		task, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+		if task.Done {
+			return fmt.Errorf("task #%d is already done", task.ID)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return runSession(ctx, phaseRecorder(openHistory(), taskCounter(store, task.ID)), task.Title)
+	},
+}
+
+var taskImportCmd = &cobra.Command{
+	Use:   "import <todo.txt>",
+	Short: "Add the open tasks of a todo.txt file to the task list",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lines, err := todotxt.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		store, err := openTasks()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		return importTodo(cmd.OutOrStdout(), store, list, lines)
+	},
+}
+
+// importTodo adds every open todo.txt line that is not on the task list
+// yet, so importing the same file again only picks up new lines.
+func importTodo(w io.Writer, store *tasks.Store, list []tasks.Task, lines []todotxt.Line) error {
+	known := make(map[string]bool)
+	for _, task := range list {
+		if !task.Done {
+			known[task.Title] = true
+		}
+	}
+
+	added := 0
+	for _, line := range lines {
+		title := line.Item.Label()
+		if line.Item.Done || known[title] {
+			continue
+		}
+		estimate := taskEstimate
+		if value, ok := line.Item.Tag("estimate"); ok {
+			if n, err := strconv.Atoi(value); err == nil {
+				estimate = n
+			}
+		}
+		task, err := store.Add(title, estimate)
+		if err != nil {
+			fmt.Fprintf(w, "⚠️  Skipped %s:%d: %v\n", line.Path, line.Number, err)
+			continue
+		}
+		known[title] = true
+		added++
+		fmt.Fprintf(w, "➕ Added task #%d: %s (%s)\n", task.ID, task.Title, pomodoroCount(task))
+	}
+	fmt.Fprintf(w, "📥 Imported %d task(s).\n", added)
+	return nil
+}
+
+func openTasks() (*tasks.Store, error) {
+	path, err := tasks.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return tasks.Open(path)
+}
+
+// taskCounter counts pomodoros towards a task in the task list.
+func taskCounter(store *tasks.Store, id int) func() error {
+	return func() error {
+		_, err := store.AddPomodoro(id)
+		return err
+	}
+}
+
+func parseTaskID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid task id %q", arg)
+	}
+	return id, nil
+}
+
+func pomodoroCount(task tasks.Task) string {
+	return fmt.Sprintf("%d/%d 🍅", task.Pomodoros, task.Estimate)
+}
+
+func writeTasks(w io.Writer, list []tasks.Task, all bool) error {
+	var shown []tasks.Task
+	for _, task := range list {
+		if all || !task.Done {
+			shown = append(shown, task)
+		}
+	}
+	if len(shown) == 0 {
+		_, err := fmt.Fprintln(w, "No tasks yet. Add one with: aragomodoro task add \"write RFC\"")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tPOMODOROS\tTITLE")
+	for _, task := range shown {
+		status := "open"
+		if task.Done {
+			status = "done"
+		}
+		fmt.Fprintf(tw, "#%d\t%s\t%d/%d\t%s\n", task.ID, status, task.Pomodoros, task.Estimate, task.Title)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	taskAddCmd.Flags().IntVarP(&taskEstimate, "estimate", "e", 1, "Number of pomodoros the task should take")
+	taskImportCmd.Flags().IntVarP(&taskEstimate, "estimate", "e", 1, "Number of pomodoros for lines without an estimate:N tag")
+	taskListCmd.Flags().BoolVarP(&taskListAll, "all", "a", false, "Include tasks that are done")
+	addTimerFlags(taskStartCmd.Flags())
+	addFontFlag(taskStartCmd.Flags())
+	addSoundFlags(taskStartCmd.Flags())
+
+	taskCmd.AddCommand(taskAddCmd, taskListCmd, taskDoneCmd, taskEstimateCmd, taskStartCmd, taskImportCmd)
+	rootCmd.AddCommand(taskCmd)
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+	"github.com/aureliomalheiros/aragomodoro/internal/tasks"
+	"github.com/aureliomalheiros/aragomodoro/internal/todotxt"
+)
+
+func TestTaskCmdSubcommands(t *testing.T) {
+	for _, name := range []string{"add", "list", "done", "estimate", "start", "import"} {
+		if cmd, _, err := taskCmd.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("Expected subcommand '%s' to exist", name)
+		}
+	}
+	for _, flagName := range []string{"focus", "break", "repeat", "profile"} {
+		if taskStartCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Expected task start flag '%s' to exist", flagName)
+		}
+	}
+}
+
+func TestParseTaskID(t *testing.T) {
+	tests := []struct {
+		arg     string
+		id      int
+		wantErr bool
+	}{
+		{"3", 3, false},
+		{"#12", 12, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		id, err := parseTaskID(tt.arg)
+		if (err != nil) != tt.wantErr || id != tt.id {
+			t.Errorf("parseTaskID(%q) = %d, %v", tt.arg, id, err)
+		}
+	}
+}
+
+func TestWriteTasks(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeTasks(&out, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "No tasks yet") {
+		t.Errorf("Expected a hint for an empty list, got %q", out.String())
+	}
+
+	list := []tasks.Task{
+		{ID: 1, Title: "Write report", Estimate: 3, Pomodoros: 1},
+		{ID: 2, Title: "Old chore", Estimate: 1, Pomodoros: 1, Done: true},
+	}
+	out.Reset()
+	writeTasks(&out, list, false)
+	if !strings.Contains(out.String(), "#1") || strings.Contains(out.String(), "Old chore") {
+		t.Errorf("Expected only open tasks, got %q", out.String())
+	}
+
+	out.Reset()
+	writeTasks(&out, list, true)
+	if !strings.Contains(out.String(), "1/3") || !strings.Contains(out.String(), "done") {
+		t.Errorf("Expected every task, got %q", out.String())
+	}
+}
+
+func TestTaskCmd_AddDoneList(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataDir)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		taskEstimate, taskListAll = 1, false
+	}()
+
+	for _, args := range [][]string{
+		{"task", "add", "Write", "report", "-e", "3"},
+		{"task", "add", "Review"},
+		{"task", "estimate", "#1", "4"},
+		{"task", "done", "2"},
+		{"task", "list"},
+	} {
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+
+	if !strings.Contains(out.String(), "Added task #1: Write report (0/3 🍅)") {
+		t.Errorf("Unexpected add output %q", out.String())
+	}
+	list := out.String()[strings.LastIndex(out.String(), "ID"):]
+	if !strings.Contains(list, "0/4") || strings.Contains(list, "Review") {
+		t.Errorf("Expected only the open task in the list, got %q", list)
+	}
+
+	store, err := tasks.Open(filepath.Join(dataDir, "aragomodoro", "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task, err := store.Get(2); err != nil || !task.Done {
+		t.Errorf("Expected task #2 done, got %+v, %v", task, err)
+	}
+}
+
+func TestPhaseRecorder(t *testing.T) {
+	dir := t.TempDir()
+	historyStore, err := history.Open(filepath.Join(dir, "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskStore, err := tasks.Open(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := taskStore.Add("Write report", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	record := phaseRecorder(historyStore, taskCounter(taskStore, 1))
+	records := []pomodoro.PhaseRecord{
+		{Phase: pomodoro.PhaseFocus, Start: start, End: start.Add(25 * time.Minute), Outcome: history.OutcomeCompleted, Planned: 25 * time.Minute, Actual: 25 * time.Minute},
+		{Phase: pomodoro.PhaseBreak, Start: start, End: start.Add(5 * time.Minute), Outcome: history.OutcomeCompleted, Planned: 5 * time.Minute, Actual: 5 * time.Minute},
+		{Phase: pomodoro.PhaseFocus, Start: start, End: start.Add(time.Minute), Outcome: history.OutcomeStopped, Planned: 25 * time.Minute, Actual: time.Minute},
+	}
+	for _, r := range records {
+		if err := record(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if task, _ := taskStore.Get(1); task.Pomodoros != 1 {
+		t.Errorf("Expected only the completed focus phase counted, got %+v", task)
+	}
+	if entries, _ := historyStore.Entries(); len(entries) != 3 {
+		t.Errorf("Expected every phase in the history, got %d entries", len(entries))
+	}
+
+	if err := phaseRecorder(historyStore, taskCounter(taskStore, 9))(records[0]); err == nil {
+		t.Error("Expected an error for a missing task")
+	}
+}
+
+func TestImportTodo(t *testing.T) {
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "todo.txt")
+	os.WriteFile(todoPath, []byte("(A) Write RFC +work estimate:3\nx 2024-01-02 Old chore\nReview PR @laptop\n"), 0o600)
+	store, err := tasks.Open(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := todotxt.ReadFile(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	for i := 0; i < 2; i++ {
+		list, _ := store.List()
+		if err := importTodo(&out, store, list, lines); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, _ := store.List()
+	if len(list) != 2 {
+		t.Fatalf("Expected the open lines imported once, got %+v", list)
+	}
+	if list[0].Title != "(A) Write RFC +work" || list[0].Estimate != 3 || list[1].Title != "Review PR @laptop" || list[1].Estimate != 1 {
+		t.Errorf("Unexpected tasks %+v", list)
+	}
+	if !strings.Contains(out.String(), "Imported 2 task(s)") || !strings.Contains(out.String(), "Imported 0 task(s)") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestTaskFrom(t *testing.T) {
+	if rootCmd.Flags().Lookup("task-from") == nil {
+		t.Fatal("Expected flag 'task-from' to exist")
+	}
+
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "todo.txt")
+	os.WriteFile(todoPath, []byte("x 2024-01-02 Old chore\n(B) Write RFC +work\n"), 0o600)
+
+	if _, err := openTodoLine(todoPath + ":1"); err == nil {
+		t.Error("Expected an error for a done line")
+	}
+	if _, err := openTodoLine(todoPath); err == nil {
+		t.Error("Expected an error without a line number")
+	}
+
+	line, err := openTodoLine(todoPath + ":2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	record := phaseRecorder(nil, line.AddPomodoro)
+	if err := record(pomodoro.PhaseRecord{Phase: pomodoro.PhaseFocus, Start: start, End: start.Add(25 * time.Minute), Outcome: history.OutcomeCompleted}); err != nil {
+		t.Fatal(err)
+	}
+	if err := record(pomodoro.PhaseRecord{Phase: pomodoro.PhaseBreak, Start: start, End: start.Add(5 * time.Minute), Outcome: history.OutcomeCompleted}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(todoPath); !strings.Contains(string(data), "(B) Write RFC +work pomodoros:1\n") {
+		t.Errorf("Expected the pomodoro written back, got %q", data)
+	}
+}
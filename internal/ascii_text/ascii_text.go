@@ -1,9 +1,18 @@
 package ascii_text
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 
-func PrintAsciiTextAragomodoro() {
-	fmt.Print(`
+	"github.com/aureliomalheiros/aragomodoro/internal/terminal"
+)
+
+// aragomodoroBanner is the hand-drawn banner, over 170 columns wide.
+const aragomodoroBanner = `
  ░▒▓██████▓▒░  ░▒▓███████▓▒░   ░▒▓██████▓▒░   ░▒▓██████▓▒░   ░▒▓██████▓▒░  ░▒▓██████████████▓▒░   ░▒▓██████▓▒░  ░▒▓███████▓▒░   ░▒▓██████▓▒░  ░▒▓███████▓▒░   ░▒▓██████▓▒░  
 ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
 ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
@@ -12,17 +21,115 @@ func PrintAsciiTextAragomodoro() {
 ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
 ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░  ░▒▓██████▓▒░   ░▒▓██████▓▒░  ░▒▓█▓▒░░▒▓█▓▒░░▒▓█▓▒░  ░▒▓██████▓▒░  ░▒▓███████▓▒░   ░▒▓██████▓▒░  ░▒▓█▓▒░░▒▓█▓▒░  ░▒▓██████▓▒░  
                                                                                                                                                                                                                                                                           
-`)
+`
+
+// PrintAsciiTextAragomodoro prints the banner in the block font, sized to
+// the terminal like PrintBanner.
+func PrintAsciiTextAragomodoro() {
+	PrintBanner(Block)
 }
 
+// PrintAsciiTextBreak prints BREAK in the block font.
 func PrintAsciiTextBreak() {
-	fmt.Print(`
-░▒▓███████▓▒░  ░▒▓███████▓▒░  ░▒▓████████▓▒░  ░▒▓██████▓▒░  ░▒▓█▓▒░░▒▓█▓▒░ 
-░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
-░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
-░▒▓███████▓▒░  ░▒▓███████▓▒░  ░▒▓██████▓▒░   ░▒▓████████▓▒░ ░▒▓███████▓▒░  
-░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
-░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
-░▒▓███████▓▒░  ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓████████▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ 
-`)
+	fmt.Println()
+	Fprint(os.Stdout, "BREAK")
+}
+
+// bannerText is the banner for terminals too narrow for any drawn one.
+const bannerText = "🧭 Aragomodoro"
+
+// bannerColors shade the banner from gold at the top to brown at the
+// bottom, in the 256-colour palette.
+var bannerColors = []int{220, 214, 208, 172, 166, 130, 94}
+
+// compactFont draws the banner when the full one does not fit.
+var compactFont = sync.OnceValue(func() Font {
+	font, err := LoadFont("mini")
+	if err != nil {
+		panic(err)
+	}
+	return font
+})
+
+// Banner lays out the Aragomodoro banner centered in width columns: drawn
+// in font when it fits, in the compact font when that fits, and as plain
+// text otherwise. The block font gets the hand-drawn banner. color shades
+// the drawn banners with ANSI escapes.
+func Banner(font Font, width int, color bool) string {
+	lines := bannerLines(font, width)
+	pad := strings.Repeat(" ", max(width-linesWidth(lines), 0)/2)
+
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, line := range lines {
+		b.WriteString(pad)
+		if color && len(lines) > 1 {
+			fmt.Fprintf(&b, "\033[38;5;%dm%s\033[0m", bannerColors[i*len(bannerColors)/len(lines)], line)
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func bannerLines(font Font, width int) []string {
+	full := font.Lines("Aragomodoro")
+	if font == Block {
+		full = strings.Split(strings.Trim(aragomodoroBanner, "\n"), "\n")
+	}
+	for _, lines := range [][]string{full, compactFont().Lines("Aragomodoro")} {
+		lines = trimLines(lines)
+		if linesWidth(lines) <= width {
+			return lines
+		}
+	}
+	return []string{bannerText}
+}
+
+// trimLines drops trailing spaces and blank lines, which would only make
+// the banner wrap sooner.
+func trimLines(lines []string) []string {
+	trimmed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimRight(line, " "); line != "" {
+			trimmed = append(trimmed, line)
+		}
+	}
+	return trimmed
+}
+
+func linesWidth(lines []string) int {
+	width := 0
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+	return width
+}
+
+// PrintBanner prints the banner in font sized to the terminal, in colour
+// unless stdout is not a terminal or NO_COLOR is set.
+func PrintBanner(font Font) {
+	fd := int(os.Stdout.Fd())
+	width, _, err := terminal.Size(fd)
+	if err != nil {
+		width = columns()
+	}
+	fmt.Print(Banner(font, width, useColor(terminal.IsTerminal(fd))))
+}
+
+// useColor reports whether to colour output to a terminal or not, as
+// https://no-color.org asks.
+func useColor(terminal bool) bool {
+	return terminal && os.Getenv("NO_COLOR") == ""
+}
+
+// columns is how wide output that is not a terminal is laid out: $COLUMNS,
+// or 80.
+func columns() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
 }
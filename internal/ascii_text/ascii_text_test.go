@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestPrintAsciiTextAragomodoro(t *testing.T) {
@@ -67,6 +69,102 @@ func TestPrintFunctions_NoErrors(t *testing.T) {
 	PrintAsciiTextBreak()
 }
 
+func TestBanner_Variants(t *testing.T) {
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{200, "░▒▓██████████████▓▒░"},
+		{80, `|_| |_) |_| /__`},
+		{30, bannerText},
+	}
+	for _, tt := range tests {
+		banner := Banner(Block, tt.width, false)
+		if !strings.Contains(banner, tt.want) {
+			t.Errorf("Banner at %d columns is missing %q:\n%s", tt.width, tt.want, banner)
+		}
+		for _, line := range strings.Split(banner, "\n") {
+			if n := utf8.RuneCountInString(line); n > tt.width {
+				t.Errorf("Banner at %d columns has a %d column line: %q", tt.width, n, line)
+			}
+		}
+	}
+}
+
+func TestBanner_Centered(t *testing.T) {
+	lines := strings.Split(strings.Trim(Banner(Block, 80, false), "\n"), "\n")
+	compact := trimLines(compactFont().Lines("Aragomodoro"))
+	pad := (80 - linesWidth(compact)) / 2
+	for i, line := range lines {
+		if line != strings.Repeat(" ", pad)+compact[i] {
+			t.Errorf("line %d = %q, want it indented by %d", i, line, pad)
+		}
+	}
+}
+
+func TestBanner_Font(t *testing.T) {
+	font, err := LoadFont("banner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if banner := Banner(font, 200, false); !strings.Contains(banner, strings.TrimRight(font.Lines("Aragomodoro")[0], " ")) {
+		t.Errorf("Banner should be drawn in the chosen font:\n%s", banner)
+	}
+}
+
+func TestBanner_Color(t *testing.T) {
+	if banner := Banner(Block, 200, true); !strings.Contains(banner, "\033[38;5;220m") || !strings.Contains(banner, "\033[0m") {
+		t.Errorf("coloured banner has no colour escapes: %q", banner)
+	}
+	if banner := Banner(Block, 200, false); strings.Contains(banner, "\033[") {
+		t.Errorf("plain banner has escapes: %q", banner)
+	}
+	if banner := Banner(Block, 20, true); strings.Contains(banner, "\033[") {
+		t.Errorf("text banner should not be coloured: %q", banner)
+	}
+}
+
+// Output that is not a terminal is laid out for $COLUMNS and not coloured.
+func TestPrintBanner_NotATTY(t *testing.T) {
+	t.Setenv("COLUMNS", "60")
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	PrintBanner(Block)
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if buf.String() != Banner(Block, 60, false) {
+		t.Errorf("PrintBanner wrote %q, want %q", buf.String(), Banner(Block, 60, false))
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if !useColor(true) || useColor(false) {
+		t.Error("only a terminal should get colour")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if useColor(true) {
+		t.Error("NO_COLOR should turn colour off")
+	}
+}
+
+func TestColumns(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if got := columns(); got != 80 {
+		t.Errorf("columns() = %d without $COLUMNS, want 80", got)
+	}
+	t.Setenv("COLUMNS", "132")
+	if got := columns(); got != 132 {
+		t.Errorf("columns() = %d, want 132", got)
+	}
+}
+
 func BenchmarkPrintAsciiTextAragomodoro(b *testing.B) {
 	// Redirect output to avoid cluttering test output
 	old := os.Stdout
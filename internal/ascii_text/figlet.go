@@ -0,0 +1,362 @@
+package ascii_text
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Horizontal layout bits of a FIGlet font, as in the full layout field of
+// the header. The low six bits pick the smushing rules.
+const (
+	smushEqual     = 1
+	smushLowline   = 2
+	smushHierarchy = 4
+	smushPair      = 8
+	smushBigX      = 16
+	smushHardblank = 32
+	smushRules     = 63
+
+	layoutFitting  = 64
+	layoutSmushing = 128
+)
+
+// deutsch are the characters a font may define after '~', in order.
+var deutsch = []rune{'Ä', 'Ö', 'Ü', 'ä', 'ö', 'ü', 'ß'}
+
+// FIGFont is a font in the FIGlet .flf format. Only left-to-right,
+// horizontal layout is supported; text always renders on one line.
+type FIGFont struct {
+	height    int
+	hardblank rune
+	layout    int
+	chars     map[rune][][]rune
+}
+
+// ParseFIGFont reads a FIGlet font.
+func ParseFIGFont(r io.Reader) (*FIGFont, error) {
+	scanner := bufio.NewScanner(r)
+	next := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		return strings.TrimSuffix(scanner.Text(), "\r"), true
+	}
+
+	header, _ := next()
+	font, comments, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < comments; i++ {
+		if _, ok := next(); !ok {
+			return nil, errors.New("figlet: font ends in its comments")
+		}
+	}
+
+	readChar := func() ([][]rune, bool) {
+		char := make([][]rune, font.height)
+		width := 0
+		for row := range char {
+			line, ok := next()
+			if !ok {
+				return nil, false
+			}
+			char[row] = []rune(trimEndmark(line))
+			width = max(width, len(char[row]))
+		}
+		// Ragged rows are padded so every row of a character lines up.
+		for row := range char {
+			for len(char[row]) < width {
+				char[row] = append(char[row], ' ')
+			}
+		}
+		return char, true
+	}
+
+	for r := rune(' '); r <= '~'; r++ {
+		char, ok := readChar()
+		if !ok {
+			return nil, fmt.Errorf("figlet: font ends before %q", r)
+		}
+		font.chars[r] = char
+	}
+	for _, r := range deutsch {
+		char, ok := readChar()
+		if !ok {
+			return font, scanner.Err()
+		}
+		font.chars[r] = char
+	}
+	for {
+		tag, ok := next()
+		if !ok || strings.TrimSpace(tag) == "" {
+			break
+		}
+		code, err := strconv.ParseInt(strings.Fields(tag)[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("figlet: bad code tag %q", tag)
+		}
+		char, ok := readChar()
+		if !ok {
+			return nil, fmt.Errorf("figlet: font ends in character %d", code)
+		}
+		// Negative codes are private to the font's author.
+		if code >= 0 {
+			font.chars[rune(code)] = char
+		}
+	}
+	return font, scanner.Err()
+}
+
+// parseHeader reads the first line of a font:
+//
+//	flf2a$ height baseline maxlength oldlayout comments [direction [fulllayout [codetags]]]
+//
+// where the character after flf2a is the hardblank.
+func parseHeader(header string) (*FIGFont, int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 6 || !strings.HasPrefix(fields[0], "flf2a") || len(fields[0]) == len("flf2a") {
+		return nil, 0, errors.New("figlet: not a FIGlet font")
+	}
+	numbers := make([]int, len(fields)-1)
+	for i, field := range fields[1:] {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, 0, fmt.Errorf("figlet: bad header field %q", field)
+		}
+		numbers[i] = n
+	}
+	height, oldLayout, comments := numbers[0], numbers[3], numbers[4]
+	if height < 1 {
+		return nil, 0, fmt.Errorf("figlet: bad height %d", height)
+	}
+
+	font := &FIGFont{
+		height:    height,
+		hardblank: []rune(fields[0][len("flf2a"):])[0],
+		chars:     make(map[rune][][]rune),
+	}
+	switch {
+	case len(numbers) >= 7:
+		font.layout = numbers[6] & (smushRules | layoutFitting | layoutSmushing)
+	case oldLayout < 0:
+		// Full width: characters are set side by side.
+	case oldLayout == 0:
+		font.layout = layoutFitting
+	default:
+		font.layout = layoutSmushing | oldLayout&smushRules
+	}
+	return font, comments, nil
+}
+
+// trimEndmark strips the endmark, the last character of every line,
+// which is doubled on the last line of a character.
+func trimEndmark(line string) string {
+	line = strings.TrimRight(line, " \t")
+	if line == "" {
+		return ""
+	}
+	runes := []rune(line)
+	return strings.TrimRight(line, string(runes[len(runes)-1]))
+}
+
+func (f *FIGFont) Height() int {
+	return f.height
+}
+
+// Lines renders text one row per string. Characters the font lacks are
+// left out.
+func (f *FIGFont) Lines(text string) []string {
+	out := make([][]rune, f.height)
+	previous := 0
+	for _, r := range text {
+		char, ok := f.chars[r]
+		if !ok {
+			continue
+		}
+		width := len(char[0])
+		overlap := f.overlap(out, char, previous)
+		for row, add := range char {
+			line := out[row]
+			for k := 0; k < overlap; k++ {
+				if column := len(line) - overlap + k; column >= 0 {
+					if smushed := f.smush(line[column], add[k], previous, width); smushed != 0 {
+						line[column] = smushed
+					}
+				}
+			}
+			out[row] = append(line, add[overlap:]...)
+		}
+		previous = width
+	}
+
+	lines := make([]string, f.height)
+	for row, line := range out {
+		lines[row] = strings.ReplaceAll(string(line), string(f.hardblank), " ")
+	}
+	return lines
+}
+
+// overlap is how many columns char can slide into out: as far as every
+// row allows, stopping where a row's characters would touch, or one column
+// further where they smush into one.
+func (f *FIGFont) overlap(out [][]rune, char [][]rune, previous int) int {
+	if f.layout&(layoutFitting|layoutSmushing) == 0 {
+		return 0
+	}
+	width := len(char[0])
+	overlap := width
+	for row, add := range char {
+		line := out[row]
+		end := len(line)
+		for end > 0 && line[end-1] == ' ' {
+			end--
+		}
+		start := 0
+		for start < width && add[start] == ' ' {
+			start++
+		}
+
+		amount := start + len(line) - end
+		if end > 0 && start < width && f.smush(line[end-1], add[start], previous, width) != 0 {
+			amount++
+		}
+		overlap = min(overlap, amount)
+	}
+	return overlap
+}
+
+// smush returns the character left and right combine into where they
+// overlap, or 0 when they cannot. previous and width are the widths of
+// the characters they come from.
+func (f *FIGFont) smush(left, right rune, previous, width int) rune {
+	switch {
+	case left == ' ':
+		return right
+	case right == ' ':
+		return left
+	case previous < 2 || width < 2, f.layout&layoutSmushing == 0:
+		return 0
+	}
+
+	rules := f.layout & smushRules
+	if rules == 0 {
+		// Universal smushing: the later character wins over anything but
+		// a hardblank.
+		if right == f.hardblank {
+			return left
+		}
+		return right
+	}
+
+	if rules&smushHardblank != 0 && left == f.hardblank && right == f.hardblank {
+		return left
+	}
+	if left == f.hardblank || right == f.hardblank {
+		return 0
+	}
+	if rules&smushEqual != 0 && left == right {
+		return left
+	}
+	if rules&smushLowline != 0 {
+		if left == '_' && strings.ContainsRune(`|/\[]{}()<>`, right) {
+			return right
+		}
+		if right == '_' && strings.ContainsRune(`|/\[]{}()<>`, left) {
+			return left
+		}
+	}
+	if rules&smushHierarchy != 0 {
+		l, r := hierarchyClass(left), hierarchyClass(right)
+		if l >= 0 && r >= 0 && l != r {
+			if l > r {
+				return left
+			}
+			return right
+		}
+	}
+	if rules&smushPair != 0 {
+		switch string([]rune{left, right}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+	if rules&smushBigX != 0 {
+		switch string([]rune{left, right}) {
+		case `/\`:
+			return '|'
+		case `\/`:
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+	return 0
+}
+
+// hierarchyClass ranks the characters the hierarchy rule smushes; the
+// higher class replaces the lower.
+func hierarchyClass(r rune) int {
+	for i, class := range []string{"|", `/\`, "[]", "{}", "()", "<>"} {
+		if strings.ContainsRune(class, r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Width is how many columns Lines(text) takes.
+func (f *FIGFont) Width(text string) int {
+	return len([]rune(f.Lines(text)[0]))
+}
+
+// Render returns text in the font, each row ending in a newline.
+func (f *FIGFont) Render(text string) string {
+	return strings.Join(f.Lines(text), "\n") + "\n"
+}
+
+// Fprint writes text to w in the font.
+func (f *FIGFont) Fprint(w io.Writer, text string) error {
+	_, err := io.WriteString(w, f.Render(text))
+	return err
+}
+
+//go:embed fonts/*.flf
+var fontFiles embed.FS
+
+// Fonts lists the fonts LoadFont knows by name.
+var Fonts = []string{"block", "banner", "mini"}
+
+// LoadFont returns the font called name, one of Fonts, or else reads the
+// FIGlet font file at that path.
+func LoadFont(name string) (Font, error) {
+	if name == "" || name == "block" {
+		return Block, nil
+	}
+	if data, err := fontFiles.ReadFile("fonts/" + name + ".flf"); err == nil {
+		return ParseFIGFont(bytes.NewReader(data))
+	}
+
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && !strings.ContainsAny(name, `./\`) {
+		return nil, fmt.Errorf("unknown font %q, expected one of %s or a .flf file", name, strings.Join(Fonts, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	font, err := ParseFIGFont(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return font, nil
+}
@@ -0,0 +1,173 @@
+package ascii_text
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func loadFIGFont(t *testing.T, name string) *FIGFont {
+	t.Helper()
+	font, err := LoadFont(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	figFont, ok := font.(*FIGFont)
+	if !ok {
+		t.Fatalf("LoadFont(%q) is a %T, want a FIGlet font", name, font)
+	}
+	return figFont
+}
+
+func TestFIGFont_Golden(t *testing.T) {
+	tests := []struct {
+		golden string
+		font   string
+		text   string
+	}{
+		{"banner", "banner", "Focus 25:00"},
+		{"mini", "mini", "Long break 4:59!"},
+		{"smush", "testdata/smush.flf", "ab cd ef gh ii Ä☺"},
+		{"universal", "testdata/universal.flf", "ab ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got := loadFIGFont(t, tt.font).Render(tt.text)
+
+			path := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("Render(%q) =\n%s\nwant\n%s", tt.text, got, want)
+			}
+		})
+	}
+}
+
+func TestFIGFont_Smushing(t *testing.T) {
+	font := loadFIGFont(t, "testdata/smush.flf")
+	tests := []struct {
+		text string
+		want []string
+	}{
+		// equal character, underscore and big X
+		{"ab", []string{"a|b", "a/b", "a|b"}},
+		// hierarchy, opposite pair and big X
+		{"cd", []string{"c(d", "c|d", "cYd"}},
+		// big X, hardblank and opposite pair
+		{"ef", []string{"eXf", "e f", "e|f"}},
+		// characters that do not smush only touch
+		{"gh", []string{"gxyh", "g  h", "g  h"}},
+		// nor do characters a column wide
+		{"ii", []string{"||", "||", "||"}},
+	}
+	for _, tt := range tests {
+		got := font.Lines(tt.text)
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("Lines(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFIGFont_Characters(t *testing.T) {
+	font := loadFIGFont(t, "testdata/smush.flf")
+	if got := font.Lines("☺")[0]; got != "(:" {
+		t.Errorf("code-tagged character = %q, want %q", got, "(:")
+	}
+	if got := font.Lines("Ä")[1]; got != "AE" {
+		t.Errorf("German character = %q, want %q", got, "AE")
+	}
+	if _, ok := font.chars[-1]; ok {
+		t.Error("characters with negative codes should be left out")
+	}
+	if got := font.Lines("a€")[0]; got != "a|" {
+		t.Errorf("Lines skips characters the font lacks, got %q", got)
+	}
+	if font.Height() != 3 || font.Width("ab") != 3 {
+		t.Errorf("Height() = %d, Width(ab) = %d, want 3 and 3", font.Height(), font.Width("ab"))
+	}
+}
+
+func TestParseFIGFont_CRLF(t *testing.T) {
+	data, err := os.ReadFile("testdata/universal.flf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ParseFIGFont(bytes.NewReader(bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := font.Lines("ab"); got[0] != "a+b" || got[1] != "axb" {
+		t.Errorf("Lines(ab) = %q", got)
+	}
+}
+
+func TestParseFIGFont_Errors(t *testing.T) {
+	tests := map[string]string{
+		"not a font":    "hello\n",
+		"no hardblank":  "flf2a 3 3 4 0 0\n",
+		"bad field":     "flf2a$ three 3 4 0 0\n",
+		"no height":     "flf2a$ 0 0 4 0 0\n",
+		"few fields":    "flf2a$ 3 3 4\n",
+		"comments":      "flf2a$ 1 1 4 0 2\none comment\n",
+		"truncated":     "flf2a$ 1 1 4 0 0\n$@@\n!@@\n",
+		"bad code tag":  "flf2a$ 1 1 4 0 0\n" + strings.Repeat("x@@\n", 95+7) + "oops\nx@@\n",
+		"truncated tag": "flf2a$ 1 1 4 0 0\n" + strings.Repeat("x@@\n", 95+7) + "300\n",
+	}
+	for name, data := range tests {
+		if _, err := ParseFIGFont(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	for _, name := range Fonts {
+		font, err := LoadFont(name)
+		if err != nil {
+			t.Errorf("LoadFont(%q): %v", name, err)
+			continue
+		}
+		if lines := font.Lines("25:00"); len(lines) != font.Height() || font.Width("25:00") == 0 {
+			t.Errorf("font %q renders 25:00 as %q", name, lines)
+		}
+	}
+	if font, err := LoadFont(""); err != nil || font != Block {
+		t.Errorf("LoadFont(\"\") = %v, %v, want the block font", font, err)
+	}
+
+	if _, err := LoadFont("gothic"); err == nil || !strings.Contains(err.Error(), "unknown font") {
+		t.Errorf("LoadFont(gothic) error = %v, want an unknown font", err)
+	}
+	if _, err := LoadFont(filepath.Join(t.TempDir(), "missing.flf")); err == nil {
+		t.Error("LoadFont of a missing file should fail")
+	}
+	path := filepath.Join(t.TempDir(), "broken.flf")
+	os.WriteFile(path, []byte("not a font\n"), 0o644)
+	if _, err := LoadFont(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadFont of a broken file error = %v, want it to name the file", err)
+	}
+}
+
+func TestFIGFont_Fprint(t *testing.T) {
+	font := loadFIGFont(t, "mini")
+	var buf bytes.Buffer
+	if err := font.Fprint(&buf, "25:00"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != font.Render("25:00") || strings.Count(buf.String(), "\n") != font.Height() {
+		t.Errorf("Fprint wrote %q", buf.String())
+	}
+}
@@ -0,0 +1,543 @@
+package ascii_text
+
+import (
+	"io"
+	"strings"
+)
+
+// GlyphHeight is the number of rows in a big glyph.
+const GlyphHeight = 7
+
+// Font draws text as rows of a fixed height.
+type Font interface {
+	Lines(text string) []string
+	Width(text string) int
+	Height() int
+}
+
+// Block is the font the banners are drawn in, from the glyph table below.
+var Block Font = blockFont{}
+
+type blockFont struct{}
+
+func (blockFont) Lines(text string) []string { return Lines(text) }
+func (blockFont) Width(text string) int      { return Width(text) }
+func (blockFont) Height() int                { return GlyphHeight }
+
+// glyphs are drawn on a grid of '#' cells, eight wide for most letters and
+// digits. Each cell becomes a █ shaded on both sides; see renderCells.
+var glyphs = map[rune][GlyphHeight]string{
+	'0': {
+		".######.",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		".######.",
+	},
+	'1': {
+		"...##...",
+		"..###...",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+		".######.",
+	},
+	'2': {
+		".######.",
+		"#......#",
+		".......#",
+		".######.",
+		"#.......",
+		"#.......",
+		"########",
+	},
+	'3': {
+		".######.",
+		"#......#",
+		".......#",
+		"..#####.",
+		".......#",
+		"#......#",
+		".######.",
+	},
+	'4': {
+		"#......#",
+		"#......#",
+		"#......#",
+		"########",
+		".......#",
+		".......#",
+		".......#",
+	},
+	'5': {
+		"########",
+		"#.......",
+		"#.......",
+		"#######.",
+		".......#",
+		"#......#",
+		".######.",
+	},
+	'6': {
+		".######.",
+		"#.......",
+		"#.......",
+		"#######.",
+		"#......#",
+		"#......#",
+		".######.",
+	},
+	'7': {
+		"########",
+		".......#",
+		"......#.",
+		".....#..",
+		"....#...",
+		"....#...",
+		"....#...",
+	},
+	'8': {
+		".######.",
+		"#......#",
+		"#......#",
+		".######.",
+		"#......#",
+		"#......#",
+		".######.",
+	},
+	'9': {
+		".######.",
+		"#......#",
+		"#......#",
+		".#######",
+		".......#",
+		".......#",
+		".######.",
+	},
+	':': {
+		"..",
+		"##",
+		"..",
+		"..",
+		"..",
+		"##",
+		"..",
+	},
+	'A': {
+		".######.",
+		"#......#",
+		"#......#",
+		"########",
+		"#......#",
+		"#......#",
+		"#......#",
+	},
+	'B': {
+		"#######.",
+		"#......#",
+		"#......#",
+		"#######.",
+		"#......#",
+		"#......#",
+		"#######.",
+	},
+	'C': {
+		".######.",
+		"#......#",
+		"#.......",
+		"#.......",
+		"#.......",
+		"#......#",
+		".######.",
+	},
+	'D': {
+		"#######.",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#######.",
+	},
+	'E': {
+		"########",
+		"#.......",
+		"#.......",
+		"######..",
+		"#.......",
+		"#.......",
+		"########",
+	},
+	'F': {
+		"########",
+		"#.......",
+		"#.......",
+		"######..",
+		"#.......",
+		"#.......",
+		"#.......",
+	},
+	'G': {
+		".######.",
+		"#......#",
+		"#.......",
+		"#....###",
+		"#......#",
+		"#......#",
+		".######.",
+	},
+	'H': {
+		"#......#",
+		"#......#",
+		"#......#",
+		"########",
+		"#......#",
+		"#......#",
+		"#......#",
+	},
+	'I': {
+		".######.",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+		".######.",
+	},
+	'J': {
+		".....###",
+		"......#.",
+		"......#.",
+		"......#.",
+		"#.....#.",
+		"#.....#.",
+		".#####..",
+	},
+	'K': {
+		"#......#",
+		"#......#",
+		"#......#",
+		"#######.",
+		"#......#",
+		"#......#",
+		"#......#",
+	},
+	'L': {
+		"#.......",
+		"#.......",
+		"#.......",
+		"#.......",
+		"#.......",
+		"#.......",
+		"########",
+	},
+	'M': {
+		".#############.",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+	},
+	'N': {
+		"#......#",
+		"##.....#",
+		"#.#....#",
+		"#..#...#",
+		"#...#..#",
+		"#....#.#",
+		"#.....##",
+	},
+	'O': {
+		".######.",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		".######.",
+	},
+	'P': {
+		"#######.",
+		"#......#",
+		"#......#",
+		"#######.",
+		"#.......",
+		"#.......",
+		"#.......",
+	},
+	'Q': {
+		".######.",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#....#.#",
+		"#.....#.",
+		".#####.#",
+	},
+	'R': {
+		"#######.",
+		"#......#",
+		"#......#",
+		"#######.",
+		"#......#",
+		"#......#",
+		"#......#",
+	},
+	'S': {
+		".######.",
+		"#......#",
+		"#.......",
+		".######.",
+		".......#",
+		"#......#",
+		".######.",
+	},
+	'T': {
+		"########",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+	},
+	'U': {
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		".######.",
+	},
+	'V': {
+		"#......#",
+		"#......#",
+		"#......#",
+		"#......#",
+		".#....#.",
+		"..#..#..",
+		"...##...",
+	},
+	'W': {
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		"#......#......#",
+		".######.######.",
+	},
+	'X': {
+		"#......#",
+		".#....#.",
+		"..#..#..",
+		"...##...",
+		"..#..#..",
+		".#....#.",
+		"#......#",
+	},
+	'Y': {
+		"#......#",
+		".#....#.",
+		"..#..#..",
+		"...##...",
+		"...##...",
+		"...##...",
+		"...##...",
+	},
+	'Z': {
+		"########",
+		"......#.",
+		".....#..",
+		"....#...",
+		"...#....",
+		"..#.....",
+		"########",
+	},
+	' ': {
+		"....",
+		"....",
+		"....",
+		"....",
+		"....",
+		"....",
+		"....",
+	},
+	'.': {
+		"..",
+		"..",
+		"..",
+		"..",
+		"..",
+		"..",
+		"##",
+	},
+	',': {
+		"..",
+		"..",
+		"..",
+		"..",
+		"..",
+		"##",
+		"#.",
+	},
+	';': {
+		"..",
+		"##",
+		"..",
+		"..",
+		"..",
+		"##",
+		"#.",
+	},
+	'!': {
+		"##",
+		"##",
+		"##",
+		"##",
+		"##",
+		"..",
+		"##",
+	},
+	'?': {
+		".######.",
+		"#......#",
+		".......#",
+		"....###.",
+		"...#....",
+		"........",
+		"...#....",
+	},
+	'-': {
+		"......",
+		"......",
+		"......",
+		"######",
+		"......",
+		"......",
+		"......",
+	},
+	'+': {
+		"......",
+		"..##..",
+		"..##..",
+		"######",
+		"..##..",
+		"..##..",
+		"......",
+	},
+	'\'': {
+		"##",
+		"##",
+		"..",
+		"..",
+		"..",
+		"..",
+		"..",
+	},
+	'/': {
+		".......#",
+		"......#.",
+		".....#..",
+		"....#...",
+		"...#....",
+		"..#.....",
+		".#......",
+	},
+	'(': {
+		".###",
+		"#...",
+		"#...",
+		"#...",
+		"#...",
+		"#...",
+		".###",
+	},
+	')': {
+		"###.",
+		"...#",
+		"...#",
+		"...#",
+		"...#",
+		"...#",
+		"###.",
+	},
+}
+
+// shades are drawn around a run of █, from the edge inwards. Where the
+// shading of two runs meets, the darker shade wins.
+var shades = []rune{' ', '░', '▒', '▓', '█'}
+
+// Lines renders text in the block font, one string per row, with glyphs
+// one column apart. Letters are upper-cased and characters the font lacks
+// are left out.
+func Lines(text string) []string {
+	rows := make([][]string, GlyphHeight)
+	for _, r := range strings.ToUpper(text) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+		for i, cells := range glyph {
+			rows[i] = append(rows[i], renderCells(cells))
+		}
+	}
+
+	lines := make([]string, GlyphHeight)
+	for i, row := range rows {
+		lines[i] = strings.Join(row, " ")
+	}
+	return lines
+}
+
+// Width is how many columns Lines(text) takes.
+func Width(text string) int {
+	width := -1
+	for _, r := range strings.ToUpper(text) {
+		if glyph, ok := glyphs[r]; ok {
+			width += len(glyph[0]) + 2*shadeWidth + 1
+		}
+	}
+	return max(width, 0)
+}
+
+// Render returns text in the block font, each row ending in a newline.
+func Render(text string) string {
+	return strings.Join(Lines(text), "\n") + "\n"
+}
+
+// Fprint writes text to w in the block font.
+func Fprint(w io.Writer, text string) error {
+	_, err := io.WriteString(w, Render(text))
+	return err
+}
+
+// shadeWidth is how far the shading reaches either side of a run.
+const shadeWidth = 3
+
+// renderCells turns a row of cells into runs of █ shaded ░▒▓ on both
+// sides, the way the banners are drawn.
+func renderCells(cells string) string {
+	levels := make([]int, len(cells)+2*shadeWidth)
+	for i := 0; i < len(cells); i++ {
+		if cells[i] != '#' {
+			continue
+		}
+		for d := -shadeWidth; d <= shadeWidth; d++ {
+			level := len(shades) - 1 - max(d, -d)
+			levels[shadeWidth+i+d] = max(levels[shadeWidth+i+d], level)
+		}
+	}
+
+	line := make([]rune, len(levels))
+	for i, level := range levels {
+		line[i] = shades[level]
+	}
+	return string(line)
+}
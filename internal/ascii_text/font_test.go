@@ -0,0 +1,97 @@
+package ascii_text
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLines(t *testing.T) {
+	lines := Lines("12:34")
+	if len(lines) != GlyphHeight {
+		t.Fatalf("got %d rows, want %d", len(lines), GlyphHeight)
+	}
+	for i, line := range lines {
+		if got := utf8.RuneCountInString(line); got != Width("12:34") {
+			t.Errorf("row %d is %d columns, want %d", i, got, Width("12:34"))
+		}
+	}
+	if Width("12:34") != 68 {
+		t.Errorf("Width(12:34) = %d, want 68", Width("12:34"))
+	}
+}
+
+func TestLines_Glyph(t *testing.T) {
+	lines := Lines("0")
+	if lines[0] != " ░▒▓██████▓▒░ " {
+		t.Errorf("top of 0 = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "░▒▓█▓▒░") {
+		t.Errorf("side of 0 = %q", lines[1])
+	}
+}
+
+func TestLines_SkipsUnknown(t *testing.T) {
+	if got, want := Lines("1€"), Lines("1"); got[0] != want[0] {
+		t.Errorf("Lines(1€) = %q, want %q", got[0], want[0])
+	}
+	if Width("") != 0 {
+		t.Errorf("Width(\"\") = %d, want 0", Width(""))
+	}
+}
+
+func TestGlyphs_Rectangular(t *testing.T) {
+	for r, glyph := range glyphs {
+		for i, cells := range glyph {
+			if len(cells) != len(glyph[0]) {
+				t.Errorf("glyph %q row %d is %d cells, want %d", r, i, len(cells), len(glyph[0]))
+			}
+			if strings.Trim(cells, "#.") != "" {
+				t.Errorf("glyph %q row %d has stray characters: %q", r, i, cells)
+			}
+		}
+	}
+}
+
+func TestLines_IgnoresCase(t *testing.T) {
+	lower, upper := Lines("focus"), Lines("FOCUS")
+	for i := range upper {
+		if lower[i] != upper[i] {
+			t.Errorf("row %d: %q, want %q", i, lower[i], upper[i])
+		}
+	}
+}
+
+// The shading of runs close together merges, darker shade first, as on
+// the crossbar of the G in the banner.
+func TestLines_MergesShading(t *testing.T) {
+	if got, want := Lines("G")[3], "░▒▓█▓▒▒▓███▓▒░"; got != want {
+		t.Errorf("crossbar of G = %q, want %q", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	want := []string{
+		"░▒▓███████▓▒░  ░▒▓███████▓▒░  ░▒▓████████▓▒░  ░▒▓██████▓▒░  ░▒▓█▓▒░░▒▓█▓▒░",
+		"░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░",
+		"░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░",
+		"░▒▓███████▓▒░  ░▒▓███████▓▒░  ░▒▓██████▓▒░   ░▒▓████████▓▒░ ░▒▓███████▓▒░ ",
+		"░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░",
+		"░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░        ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░",
+		"░▒▓███████▓▒░  ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓████████▓▒░ ░▒▓█▓▒░░▒▓█▓▒░ ░▒▓█▓▒░░▒▓█▓▒░",
+	}
+	if got := Render("BREAK"); got != strings.Join(want, "\n")+"\n" {
+		t.Errorf("Render(BREAK) =\n%s", got)
+	}
+}
+
+func TestFprint(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Fprint(&buf, "25:00"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != Render("25:00") {
+		t.Errorf("Fprint wrote %q, want %q", buf.String(), Render("25:00"))
+	}
+}
@@ -0,0 +1,96 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func TestReal_Now(t *testing.T) {
+	before := time.Now()
+	now := Real().Now()
+	if now.Before(before) {
+		t.Errorf("Real clock went backwards: %v before %v", now, before)
+	}
+}
+
+func TestReal_Timer(t *testing.T) {
+	timer := Real().NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("Real timer did not fire")
+	}
+	if timer.Stop() {
+		t.Error("Stop on a fired timer should return false")
+	}
+}
+
+func TestFake_Advance(t *testing.T) {
+	fake := NewFake(epoch)
+	timer := fake.NewTimer(time.Second)
+
+	fake.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("Timer fired before its deadline")
+	default:
+	}
+
+	fake.Advance(time.Millisecond)
+	select {
+	case fired := <-timer.C():
+		if !fired.Equal(epoch.Add(time.Second)) {
+			t.Errorf("Expected fire time %v, got %v", epoch.Add(time.Second), fired)
+		}
+	default:
+		t.Fatal("Timer did not fire at its deadline")
+	}
+
+	if now := fake.Now(); !now.Equal(epoch.Add(time.Second)) {
+		t.Errorf("Expected now %v, got %v", epoch.Add(time.Second), now)
+	}
+}
+
+func TestFake_StopAndReset(t *testing.T) {
+	fake := NewFake(epoch)
+	timer := fake.NewTimer(time.Second)
+
+	if !timer.Stop() {
+		t.Error("Stop on an armed timer should return true")
+	}
+	fake.Advance(time.Minute)
+	select {
+	case <-timer.C():
+		t.Fatal("Stopped timer fired")
+	default:
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Reset on a stopped timer should return false")
+	}
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("Reset timer did not fire")
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	fake := NewFake(epoch)
+	armed := make(chan struct{})
+	go func() {
+		fake.BlockUntil(1)
+		close(armed)
+	}()
+
+	fake.NewTimer(time.Second)
+	select {
+	case <-armed:
+	case <-time.After(time.Second):
+		t.Fatal("BlockUntil did not return after a timer was armed")
+	}
+}
@@ -0,0 +1,117 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only moves when Advance is called. Timers fire in
+// deadline order as the clock passes them.
+type Fake struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	fake     *Fake
+	c        chan time.Time
+	deadline time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{fake: f, c: make(chan time.Time, 1)}
+	f.mu.Lock()
+	f.arm(t, d)
+	f.mu.Unlock()
+	return t
+}
+
+// Advance moves the clock forward by d, firing every armed timer whose
+// deadline falls within it.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+	for {
+		next := -1
+		for i, t := range f.timers {
+			if !t.deadline.After(end) && (next < 0 || t.deadline.Before(f.timers[next].deadline)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+
+		t := f.timers[next]
+		f.disarm(t)
+		if t.deadline.After(f.now) {
+			f.now = t.deadline
+		}
+		select {
+		case t.c <- f.now:
+		default:
+		}
+	}
+	f.now = end
+}
+
+// BlockUntil waits until at least n timers are armed, which lets a test
+// know the code under test is waiting on the clock before advancing it.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) arm(t *fakeTimer, d time.Duration) {
+	t.deadline = f.now.Add(d)
+	f.timers = append(f.timers, t)
+	f.cond.Broadcast()
+}
+
+func (f *Fake) disarm(t *fakeTimer) bool {
+	for i, armed := range f.timers {
+		if armed == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			f.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	return t.fake.disarm(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	wasArmed := t.fake.disarm(t)
+	t.fake.arm(t, d)
+	return wasArmed
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in defaults, used for anything neither the config file nor a flag
+// sets.
+const (
+	DefaultFocus          = 25
+	DefaultBreak          = 5
+	DefaultLongBreak      = 15
+	DefaultLongBreakEvery = 4
+	DefaultRepeat         = 1
+)
+
+// Settings are timer options as they appear in the config file. A nil
+// field was not set, so a lower layer's value shows through.
+type Settings struct {
+	Focus          *int  `yaml:"focus"`
+	Break          *int  `yaml:"break"`
+	LongBreak      *int  `yaml:"long_break"`
+	LongBreakEvery *int  `yaml:"long_break_every"`
+	Repeat         *int  `yaml:"repeat"`
+	Continue       *bool `yaml:"continue"`
+}
+
+// Config is the config file. Top-level settings apply to every run and
+// a profile's settings go on top of them.
+//
+//	focus: 30
+//	default_profile: deep-work
+//	profiles:
+//	  deep-work:
+//	    focus: 50
+//	    break: 10
+//	    repeat: 4
+type Config struct {
+	Settings       `yaml:",inline"`
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]Settings `yaml:"profiles"`
+}
+
+func Defaults() Settings {
+	return Settings{
+		Focus:          intPtr(DefaultFocus),
+		Break:          intPtr(DefaultBreak),
+		LongBreak:      intPtr(DefaultLongBreak),
+		LongBreakEvery: intPtr(DefaultLongBreakEvery),
+		Repeat:         intPtr(DefaultRepeat),
+		Continue:       boolPtr(false),
+	}
+}
+
+// DefaultPath is config.yaml in the aragomodoro config directory.
+func DefaultPath() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("config: %w", err)
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// Load reads the config file at path. A missing file is an empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	if config.DefaultProfile != "" {
+		if _, ok := config.Profiles[config.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("config: %s: default_profile %q is not defined", path, config.DefaultProfile)
+		}
+	}
+	return &config, nil
+}
+
+// Resolve layers the named profile, or the default profile when name is
+// empty, over the top-level settings. Fields neither sets stay nil.
+func (c *Config) Resolve(name string) (Settings, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return c.Settings, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return Settings{}, fmt.Errorf("unknown profile %q: no profiles are defined", name)
+		}
+		return Settings{}, fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	return c.Settings.Merge(profile), nil
+}
+
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Merge returns s with every field that over sets replaced.
+func (s Settings) Merge(over Settings) Settings {
+	if over.Focus != nil {
+		s.Focus = over.Focus
+	}
+	if over.Break != nil {
+		s.Break = over.Break
+	}
+	if over.LongBreak != nil {
+		s.LongBreak = over.LongBreak
+	}
+	if over.LongBreakEvery != nil {
+		s.LongBreakEvery = over.LongBreakEvery
+	}
+	if over.Repeat != nil {
+		s.Repeat = over.Repeat
+	}
+	if over.Continue != nil {
+		s.Continue = over.Continue
+	}
+	return s
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
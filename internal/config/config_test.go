@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+focus: 30
+long_break_every: 3
+default_profile: study
+profiles:
+  deep-work:
+    focus: 50
+    break: 10
+    repeat: 4
+  study:
+    break: 7
+    continue: true
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Missing(t *testing.T) {
+	config, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("A missing file should be an empty config, got %v", err)
+	}
+	settings, err := config.Resolve("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(settings, Settings{}) {
+		t.Errorf("Expected no settings, got %+v", settings)
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	if _, err := Load(writeConfig(t, "")); err != nil {
+		t.Errorf("An empty file should be an empty config, got %v", err)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"Syntax", "focus: [", "config.yaml"},
+		{"UnknownField", "focuss: 30", "focuss"},
+		{"WrongType", "focus: long", "line 1"},
+		{"MissingDefaultProfile", "default_profile: nope", "nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error to mention %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	config, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile  string
+		expected Settings
+	}{
+		{"", Settings{Focus: intPtr(30), Break: intPtr(7), LongBreakEvery: intPtr(3), Continue: boolPtr(true)}},
+		{"study", Settings{Focus: intPtr(30), Break: intPtr(7), LongBreakEvery: intPtr(3), Continue: boolPtr(true)}},
+		{"deep-work", Settings{Focus: intPtr(50), Break: intPtr(10), LongBreakEvery: intPtr(3), Repeat: intPtr(4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			settings, err := config.Resolve(tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(settings, tt.expected) {
+				t.Errorf("Expected %s, got %s", describe(tt.expected), describe(settings))
+			}
+		})
+	}
+}
+
+func TestResolve_UnknownProfile(t *testing.T) {
+	config, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = config.Resolve("gaming")
+	if err == nil || !strings.Contains(err.Error(), "deep-work, study") {
+		t.Errorf("Expected the known profiles to be listed, got %v", err)
+	}
+
+	if _, err := (&Config{}).Resolve("gaming"); err == nil {
+		t.Error("Expected error with no profiles defined")
+	}
+}
+
+func TestMerge_OverDefaults(t *testing.T) {
+	settings := Defaults().Merge(Settings{Focus: intPtr(50)})
+	if *settings.Focus != 50 || *settings.Break != DefaultBreak || *settings.Continue {
+		t.Errorf("Unexpected settings %s", describe(settings))
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join("/tmp/xdg", "aragomodoro", "config.yaml") {
+		t.Errorf("Unexpected path %q", path)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/frodo")
+	path, err = DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join("/home/frodo", ".config", "aragomodoro", "config.yaml") {
+		t.Errorf("Unexpected path %q", path)
+	}
+}
+
+// describe prints the fields that are set, since %+v only shows pointers.
+func describe(s Settings) string {
+	var parts []string
+	v := reflect.ValueOf(s)
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Field(i); !field.IsNil() {
+			parts = append(parts, fmt.Sprintf("%s=%v", v.Type().Field(i).Name, field.Elem().Interface()))
+		}
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
@@ -0,0 +1,58 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+)
+
+// ErrNotRunning means no timer listens on the socket.
+var ErrNotRunning = errors.New("no timer or daemon is running")
+
+// Send asks the timer listening at path to run command and returns its
+// status afterwards.
+func Send(path, command string) (Status, error) {
+	return Do(path, Request{Command: command})
+}
+
+// Do sends req to the timer listening at path and returns its status
+// afterwards.
+func Do(path string, req Request) (Status, error) {
+	// A socket in a directory someone else controls may not be ours.
+	if err := xdg.CheckPrivate(filepath.Dir(path)); errors.Is(err, os.ErrNotExist) {
+		return Status{}, ErrNotRunning
+	} else if err != nil {
+		return Status{}, fmt.Errorf("control: %w", err)
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+		return Status{}, ErrNotRunning
+	}
+	if err != nil {
+		return Status{}, fmt.Errorf("control: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(connTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Status{}, fmt.Errorf("control: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Status{}, fmt.Errorf("control: %w", err)
+	}
+	if !resp.OK {
+		return Status{}, errors.New(resp.Error)
+	}
+	if resp.Status == nil {
+		return Status{}, errors.New("control: response without a status")
+	}
+	return *resp.Status, nil
+}
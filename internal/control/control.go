@@ -0,0 +1,82 @@
+package control
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+)
+
+// Commands a running timer accepts. Every request is answered with the
+// timer's status after the command. Only a daemon accepts start.
+const (
+	CommandStart  = "start"
+	CommandStatus = "status"
+	CommandPause  = "pause"
+	CommandResume = "resume"
+	CommandSkip   = "skip"
+	CommandStop   = "stop"
+)
+
+// Request and Response make up the protocol: the client writes one JSON
+// request on a fresh connection and reads one JSON response back.
+type Request struct {
+	Command string   `json:"command"`
+	Session *Session `json:"session,omitempty"`
+}
+
+// Session describes the session to start, with durations in minutes.
+type Session struct {
+	Focus          int    `json:"focus"`
+	Break          int    `json:"break"`
+	LongBreak      int    `json:"longBreak"`
+	LongBreakEvery int    `json:"longBreakEvery"`
+	Repeat         int    `json:"repeat"`
+	Continue       bool   `json:"continue"`
+	Task           string `json:"task,omitempty"`
+}
+
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"`
+}
+
+// Status describes the current session. Active is false once it has
+// completed or been stopped, and Phase is empty before any session.
+type Status struct {
+	Active      bool   `json:"active"`
+	Phase       string `json:"phase"`
+	Cycle       int    `json:"cycle"`
+	RepeatCount int    `json:"repeatCount"`
+	// Duration and Remaining are in seconds.
+	Duration  int        `json:"duration"`
+	Remaining int        `json:"remaining"`
+	Paused    bool       `json:"paused"`
+	Task      string     `json:"task,omitempty"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"`
+}
+
+func NewStatus(state pomodoro.State) Status {
+	status := Status{
+		Active:      state.Phase != pomodoro.PhaseCompleted,
+		Phase:       string(state.Phase),
+		Cycle:       state.Cycle,
+		RepeatCount: state.RepeatCount,
+		Duration:    int(state.Duration / time.Second),
+		Remaining:   int(state.Remaining.Round(time.Second) / time.Second),
+		Paused:      state.Paused,
+		Task:        state.Task,
+	}
+	if !state.EndsAt.IsZero() {
+		endsAt := state.EndsAt
+		status.EndsAt = &endsAt
+	}
+	return status
+}
+
+// SocketPath is where a terminal timer listens for commands.
+func SocketPath() string {
+	return filepath.Join(xdg.RuntimeDir(), "control.sock")
+}
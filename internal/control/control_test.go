@@ -0,0 +1,40 @@
+package control
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+)
+
+func TestNewStatus(t *testing.T) {
+	endsAt := time.Date(2024, 1, 1, 9, 25, 0, 0, time.UTC)
+	status := NewStatus(pomodoro.State{
+		Phase:       pomodoro.PhaseFocus,
+		Cycle:       2,
+		RepeatCount: 4,
+		Duration:    25 * time.Minute,
+		Remaining:   90*time.Second + 400*time.Millisecond,
+		EndsAt:      endsAt,
+		Task:        "write RFC",
+	})
+
+	if status.Phase != "focus" || status.Cycle != 2 || status.RepeatCount != 4 || status.Duration != 1500 || status.Remaining != 90 || status.Task != "write RFC" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if status.EndsAt == nil || !status.EndsAt.Equal(endsAt) {
+		t.Errorf("Expected the end time, got %v", status.EndsAt)
+	}
+
+	if status := NewStatus(pomodoro.State{Phase: pomodoro.PhaseFocus, Paused: true}); status.EndsAt != nil || !status.Paused {
+		t.Errorf("A paused status should have no end time, got %+v", status)
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if path := SocketPath(); path != filepath.FromSlash("/run/user/1000/aragomodoro/control.sock") {
+		t.Errorf("Unexpected socket path %q", path)
+	}
+}
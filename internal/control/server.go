@@ -0,0 +1,128 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+)
+
+// ErrRunning means another timer already listens on the socket.
+var ErrRunning = errors.New("another timer is already running")
+
+const connTimeout = 5 * time.Second
+
+// Handler answers the requests that reach the socket.
+type Handler interface {
+	Handle(req Request) Response
+}
+
+// Server lets other processes query and drive a running timer.
+type Server struct {
+	handler  Handler
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// Listen starts passing requests on the socket at path to handler. A
+// socket left behind by a timer that is gone is replaced.
+func Listen(path string, handler Handler) (*Server, error) {
+	if err := xdg.MkdirPrivate(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("control: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, ErrRunning
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("control: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("control: %w", err)
+	}
+	s := &Server{handler: handler, listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops listening, removes the socket and waits for requests in
+// flight.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(connTimeout))
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(Errorf("invalid request"))
+		return
+	}
+	json.NewEncoder(conn).Encode(s.handler.Handle(req))
+}
+
+// TimerHandler drives a single timer, such as a terminal session's.
+func TimerHandler(timer *pomodoro.Timer) Handler {
+	return timerHandler{timer: timer}
+}
+
+type timerHandler struct {
+	timer *pomodoro.Timer
+}
+
+func (h timerHandler) Handle(req Request) Response {
+	switch req.Command {
+	case CommandStatus:
+	case CommandPause:
+		h.timer.Pause()
+	case CommandResume:
+		h.timer.Resume()
+	case CommandSkip:
+		h.timer.Skip()
+	case CommandStop:
+		h.timer.Stop()
+		status := NewStatus(h.timer.State())
+		status.Active = false
+		return Response{OK: true, Status: &status}
+	case CommandStart:
+		return Errorf("a session is already running in a terminal")
+	default:
+		return Errorf("unknown command %q", req.Command)
+	}
+	status := NewStatus(h.timer.State())
+	return Response{OK: true, Status: &status}
+}
+
+// Errorf builds a response reporting a failed request.
+func Errorf(format string, args ...any) Response {
+	return Response{Error: fmt.Sprintf(format, args...)}
+}
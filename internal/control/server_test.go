@@ -0,0 +1,166 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+)
+
+// startServer runs a fake-clock timer behind a control socket.
+func startServer(t *testing.T) (string, *clock.Fake, <-chan error) {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	timer := pomodoro.NewTimer(pomodoro.Config{
+		FocusDuration: time.Minute,
+		BreakDuration: time.Minute,
+		RepeatCount:   2,
+		Task:          "write RFC",
+		Clock:         fake,
+	})
+
+	path := socketPath(t)
+	server, err := Listen(path, TimerHandler(timer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	done := make(chan error, 1)
+	go func() {
+		done <- timer.Run(context.Background(), func(pomodoro.Event) {})
+	}()
+	fake.BlockUntil(1)
+	return path, fake, done
+}
+
+func TestServer_Commands(t *testing.T) {
+	path, fake, done := startServer(t)
+
+	status, err := Send(path, CommandStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Phase != "focus" || status.Cycle != 1 || status.RepeatCount != 2 || status.Remaining != 60 || status.Paused || status.Task != "write RFC" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+
+	fake.Advance(time.Second)
+	if status, err = Send(path, CommandPause); err != nil || !status.Paused || status.EndsAt != nil {
+		t.Errorf("Expected the timer paused, got %+v, %v", status, err)
+	}
+	if status, err = Send(path, CommandResume); err != nil || status.Paused {
+		t.Errorf("Expected the timer running again, got %+v, %v", status, err)
+	}
+	if _, err = Send(path, CommandSkip); err != nil {
+		t.Fatal(err)
+	}
+	// The skip is answered as soon as the focus phase ends; the break
+	// starts right after.
+	for deadline := time.Now().Add(5 * time.Second); status.Phase != "break" && time.Now().Before(deadline); {
+		if status, err = Send(path, CommandStatus); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status.Phase != "break" {
+		t.Errorf("Expected the break after a skip, got %+v", status)
+	}
+
+	if _, err = Send(path, CommandStop); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, pomodoro.ErrStopped) {
+			t.Errorf("Expected ErrStopped, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timer did not stop")
+	}
+}
+
+func TestServer_UnknownCommand(t *testing.T) {
+	path, _, _ := startServer(t)
+
+	if _, err := Send(path, "dance"); err == nil || err.Error() != `unknown command "dance"` {
+		t.Errorf("Expected an unknown command error, got %v", err)
+	}
+}
+
+func TestListen_AlreadyRunning(t *testing.T) {
+	path, _, _ := startServer(t)
+
+	if _, err := Listen(path, TimerHandler(pomodoro.NewTimer(pomodoro.Config{RepeatCount: 1}))); !errors.Is(err, ErrRunning) {
+		t.Errorf("Expected ErrRunning, got %v", err)
+	}
+}
+
+func TestListen_StaleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.Mkdir(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A crashed timer leaves its socket file behind.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+
+	server, err := Listen(path, TimerHandler(pomodoro.NewTimer(pomodoro.Config{RepeatCount: 1})))
+	if err != nil {
+		t.Fatalf("Expected the stale socket replaced, got %v", err)
+	}
+	if _, err := Send(path, CommandStatus); err != nil {
+		t.Errorf("Expected an answer on the new socket, got %v", err)
+	}
+
+	server.Close()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the socket removed on close, got %v", err)
+	}
+}
+
+func TestSend_NotRunning(t *testing.T) {
+	if _, err := Send(socketPath(t), CommandStatus); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected ErrNotRunning, got %v", err)
+	}
+}
+
+// socketPath is a socket in a directory Listen still has to create.
+func socketPath(t *testing.T) string {
+	return filepath.Join(t.TempDir(), "aragomodoro", "control.sock")
+}
+
+func TestListen_SharedDir(t *testing.T) {
+	// Another user could have made the directory first, or pointed it
+	// somewhere else.
+	shared := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(shared, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(shared, 0o755)
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(shared, link); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := TimerHandler(pomodoro.NewTimer(pomodoro.Config{RepeatCount: 1}))
+	for _, dir := range []string{shared, link} {
+		path := filepath.Join(dir, "control.sock")
+		if server, err := Listen(path, handler); err == nil {
+			server.Close()
+			t.Errorf("Listen(%s) should refuse the directory", path)
+		}
+		if _, err := Send(path, CommandStatus); err == nil || errors.Is(err, ErrNotRunning) {
+			t.Errorf("Send(%s) error = %v, want the directory refused", path, err)
+		}
+	}
+}
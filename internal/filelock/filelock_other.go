@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package filelock
+
+import "os"
+
+// Without flock there is nothing to lock; callers still serialise their own
+// goroutines.
+func Lock(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func Unlock(f *os.File) error {
+	return nil
+}
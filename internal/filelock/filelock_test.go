@@ -0,0 +1,74 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestLock_Serialises(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock")
+
+	// Each goroutine opens its own descriptor, as another process would,
+	// and does a read-modify-write that only adds up if the lock holds.
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer f.Close()
+
+			if err := Lock(f, true); err != nil {
+				t.Error(err)
+				return
+			}
+			defer Unlock(f)
+
+			data, _ := os.ReadFile(path)
+			os.WriteFile(path, append(data, 'x'), 0o644)
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 20 {
+		t.Errorf("Expected 20 writes, got %d", len(data))
+	}
+}
+
+func TestLock_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock")
+	first, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	// Two readers may hold the lock at once; this would block otherwise.
+	if err := Lock(first, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := Lock(second, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := Unlock(first); err != nil {
+		t.Error(err)
+	}
+	if err := Unlock(second); err != nil {
+		t.Error(err)
+	}
+}
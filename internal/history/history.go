@@ -0,0 +1,206 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/filelock"
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+)
+
+const (
+	OutcomeCompleted = "completed"
+	OutcomeSkipped   = "skipped"
+	OutcomeStopped   = "stopped"
+)
+
+// Entry is one phase as it was run. Planned is the configured length and
+// Actual the time that was counted down, which leaves out pauses. Both are
+// stored as plannedSeconds and actualSeconds, so the file reads well to
+// people and other tools.
+type Entry struct {
+	Start   time.Time     `json:"start"`
+	End     time.Time     `json:"end"`
+	Type    string        `json:"type"`
+	Outcome string        `json:"outcome"`
+	Planned time.Duration `json:"-"`
+	Actual  time.Duration `json:"-"`
+	Label   string        `json:"label,omitempty"`
+}
+
+// entryJSON is an Entry as it is stored.
+type entryJSON struct {
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Type           string    `json:"type"`
+	Outcome        string    `json:"outcome"`
+	PlannedSeconds float64   `json:"plannedSeconds"`
+	ActualSeconds  float64   `json:"actualSeconds"`
+	Label          string    `json:"label,omitempty"`
+}
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entryJSON{
+		Start:          e.Start,
+		End:            e.End,
+		Type:           e.Type,
+		Outcome:        e.Outcome,
+		PlannedSeconds: e.Planned.Seconds(),
+		ActualSeconds:  e.Actual.Seconds(),
+		Label:          e.Label,
+	})
+}
+
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var stored entryJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	*e = Entry{
+		Start:   stored.Start,
+		End:     stored.End,
+		Type:    stored.Type,
+		Outcome: stored.Outcome,
+		Planned: seconds(stored.PlannedSeconds),
+		Actual:  seconds(stored.ActualSeconds),
+		Label:   stored.Label,
+	}
+	return nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
+
+// Store keeps entries as JSON lines in a single file. Appends are
+// serialised within the process by a mutex and across processes by a file
+// lock, and each entry goes out in one write so readers never see half of
+// it; a torn line left by a crash is skipped when reading.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultPath is history.jsonl in the aragomodoro data directory.
+func DefaultPath() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", fmt.Errorf("history: %w", err)
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// Open returns a store backed by path, creating its directory if needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return &Store{path: path}, nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds entry to the end of the history. A nil store records
+// nothing, so callers can run without history.
+func (s *Store) Append(entry Entry) error {
+	if s == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	defer f.Close()
+
+	if err := filelock.Lock(f, true); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	defer filelock.Unlock(f)
+
+	// Start on a fresh line if an earlier writer died halfway through one.
+	torn, err := endsMidLine(f)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if torn {
+		line = append([]byte{'\n'}, line...)
+	}
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	return f.Sync()
+}
+
+// Entries reads the whole history in the order it was written. A missing
+// file is an empty history.
+func (s *Store) Entries() ([]Entry, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return Read(s.path)
+}
+
+// Read reads the history at path like Entries, for callers that only look
+// at it and so should not create its directory the way Open does.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer f.Close()
+
+	if err := filelock.Lock(f, false); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer filelock.Unlock(f)
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return entries, nil
+}
+
+func endsMidLine(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
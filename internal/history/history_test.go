@@ -0,0 +1,168 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "data", "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestStore_AppendAndEntries(t *testing.T) {
+	store := newTestStore(t)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Start: start, End: start.Add(25 * time.Minute), Type: "focus", Outcome: OutcomeCompleted, Planned: 25 * time.Minute, Actual: 25 * time.Minute},
+		{Start: start.Add(25 * time.Minute), End: start.Add(27 * time.Minute), Type: "break", Outcome: OutcomeSkipped, Planned: 5 * time.Minute, Actual: 2 * time.Minute, Label: "reading"},
+	}
+	for _, entry := range entries {
+		if err := store.Append(entry); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	got, err := store.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("Expected %d entries, got %d", len(entries), len(got))
+	}
+	for i := range entries {
+		if !got[i].Start.Equal(entries[i].Start) || !got[i].End.Equal(entries[i].End) {
+			t.Errorf("Entry %d: times changed: %+v", i, got[i])
+		}
+		got[i].Start, got[i].End = entries[i].Start, entries[i].End
+		if got[i] != entries[i] {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, entries[i], got[i])
+		}
+	}
+}
+
+func TestStore_DurationsInSeconds(t *testing.T) {
+	store := newTestStore(t)
+	entry := Entry{Type: "focus", Outcome: OutcomeStopped, Planned: 25 * time.Minute, Actual: 90500 * time.Millisecond}
+	if err := store.Append(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line := string(data); !strings.Contains(line, `"plannedSeconds":1500,"actualSeconds":90.5`) {
+		t.Errorf("Expected the durations stored in seconds, got %s", line)
+	}
+	got, err := store.Entries()
+	if err != nil || len(got) != 1 || got[0].Planned != entry.Planned || got[0].Actual != entry.Actual {
+		t.Errorf("Entries() = %+v, %v, want the durations read back", got, err)
+	}
+}
+
+func TestStore_MissingFile(t *testing.T) {
+	store := newTestStore(t)
+
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(entries))
+	}
+}
+
+func TestStore_NilStore(t *testing.T) {
+	var store *Store
+	if err := store.Append(Entry{Type: "focus"}); err != nil {
+		t.Errorf("A nil store should ignore appends, got %v", err)
+	}
+	if entries, err := store.Entries(); err != nil || entries != nil {
+		t.Errorf("A nil store should have no entries, got %v, %v", entries, err)
+	}
+}
+
+func TestStore_ConcurrentAppends(t *testing.T) {
+	store := newTestStore(t)
+	// A second store on the same file stands in for another process.
+	other, err := Open(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(s *Store) {
+			defer wg.Done()
+			if err := s.Append(Entry{Type: "focus", Outcome: OutcomeCompleted, Label: "a fairly long label to make torn writes visible"}); err != nil {
+				t.Error(err)
+			}
+		}([]*Store{store, other}[i%2])
+	}
+	wg.Wait()
+
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 50 {
+		t.Errorf("Expected 50 entries, got %d", len(entries))
+	}
+}
+
+func TestStore_RecoversFromTornLine(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.Append(Entry{Type: "focus", Outcome: OutcomeCompleted}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(store.Path(), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"type":"bre`)
+	f.Close()
+
+	if err := store.Append(Entry{Type: "break", Outcome: OutcomeStopped}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Type != "focus" || entries[1].Type != "break" {
+		t.Errorf("Expected the torn line to be skipped, got %+v", entries)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join("/tmp/xdg", "aragomodoro", "history.jsonl") {
+		t.Errorf("Unexpected path %q", path)
+	}
+
+	t.Setenv("XDG_DATA_HOME", "relative")
+	t.Setenv("HOME", "/home/frodo")
+	path, err = DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join("/home/frodo", ".local", "share", "aragomodoro", "history.jsonl") {
+		t.Errorf("Expected relative XDG_DATA_HOME to be ignored, got %q", path)
+	}
+}
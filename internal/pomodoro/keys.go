@@ -0,0 +1,62 @@
+package pomodoro
+
+import (
+	"os"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/terminal"
+)
+
+// listenKeys reads single keypresses from stdin while the timer runs and
+// maps them onto timer controls. It does nothing when stdin is not a
+// terminal, and reports whether keys are being read. The returned function
+// puts the terminal back the way it was; cbreak mode keeps Ctrl+C working,
+// so an interrupt cancels the session's context and the deferred restore
+// still runs.
+func listenKeys(timer *Timer) (func(), bool) {
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return func() {}, false
+	}
+	restoreMode, err := terminal.EnableCbreak(fd)
+	if err != nil {
+		return func() {}, false
+	}
+
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			if n == 1 {
+				handleKey(timer, buf[0])
+			}
+		}
+	}()
+
+	return func() {
+		restoreMode()
+	}, true
+}
+
+// keyHints lists the keys listenKeys understands.
+const keyHints = "⌨️  space: pause/resume · s: skip phase · +: five more minutes · Ctrl+C: quit"
+
+const extendStep = 5 * time.Minute
+
+func handleKey(timer *Timer, key byte) {
+	switch key {
+	case ' ':
+		if timer.State().Paused {
+			timer.Resume()
+		} else {
+			timer.Pause()
+		}
+	case 's':
+		timer.Skip()
+	case '+':
+		timer.Extend(extendStep)
+	}
+}
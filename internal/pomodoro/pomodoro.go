@@ -1,22 +1,18 @@
 package pomodoro
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aureliomalheiros/aragomodoro/internal/ascii_text"
 	"github.com/aureliomalheiros/aragomodoro/internal/sound"
 )
 
-func clearScreen() {
-	cmd := exec.Command("clear")
-	cmd.Stdout = os.Stdout
-	cmd.Run()
-	ascii_text.PrintAsciiTextAragomodoro()
-}
-
 func ValidateDurations(focusDuration, breakDuration, repeatCount int) error {
 
 	if focusDuration <= 0 || breakDuration <= 0 {
@@ -32,62 +28,207 @@ func ValidateDurations(focusDuration, breakDuration, repeatCount int) error {
 	return nil
 }
 
-func PomodoroTimer(focusDuration int, breakDuration int, repeatCount int, continueOnBreak bool) {
+// ValidateLongBreak checks the long break settings. A zero duration or
+// interval turns long breaks off.
+func ValidateLongBreak(longBreakDuration, longBreakEvery int) error {
 
-	if err := ValidateDurations(focusDuration, breakDuration, repeatCount); err != nil {
-		fmt.Println("❌", err)
-		os.Exit(1)
+	if longBreakDuration < 0 || longBreakEvery < 0 {
+		return fmt.Errorf("❌ Long break duration and interval cannot be negative.")
 	}
+	if longBreakDuration > 60 {
+		return fmt.Errorf("⚠️ Long break duration should not exceed 60 minutes.")
+	}
+
+	return nil
+}
+
+// MaxTaskLength is the longest task label, in characters.
+const MaxTaskLength = 200
+
+// ValidateTask checks a task label, which has to fit on the countdown line.
+func ValidateTask(task string) error {
 
-	if repeatCount > 1 {
-		repeatPomodoro(focusDuration, breakDuration, repeatCount)
-		return
+	if utf8.RuneCountInString(task) > MaxTaskLength {
+		return fmt.Errorf("❌ Task should not exceed %d characters.", MaxTaskLength)
 	}
+	if strings.ContainsAny(task, "\r\n") {
+		return fmt.Errorf("❌ Task must fit on a single line.")
+	}
+
+	return nil
+}
 
-	fmt.Printf("🧭 Aragomodoro begins! Focus for %d minutes.\n", focusDuration)
-	startTimer(time.Duration(focusDuration) * time.Minute)
-	sound.ThemeAragorn()
+// Session is what a terminal session runs with. Durations are in minutes,
+// as the flags give them.
+type Session struct {
+	FocusDuration     int
+	BreakDuration     int
+	LongBreakDuration int
+	LongBreakEvery    int
+	RepeatCount       int
+	ContinueOnBreak   bool
+	Task              string
+
+	// Font draws the countdown on a terminal and Cues play as phases end.
+	Font ascii_text.Font
+	Cues sound.Cues
+
+	// Record, when set, is passed every phase.
+	Record func(PhaseRecord) error
+	// Attach, when set, is handed the timer before it starts so other
+	// inputs can drive it, and the function it returns is called once the
+	// session is over.
+	Attach func(*Timer) func()
+}
 
-	fmt.Printf("🌿 Time for a break! Rest for %d minutes.\n", breakDuration)
-	startTimer(time.Duration(breakDuration) * time.Minute)
-	sound.ThemeMountDoom()
+// PomodoroTimer runs a terminal session until it completes or ctx is done.
+// The returned summary covers whatever was run, including a cut-short
+// phase.
+func PomodoroTimer(ctx context.Context, session Session) (Summary, error) {
 
-	clearScreen()
+	if err := ValidateDurations(session.FocusDuration, session.BreakDuration, session.RepeatCount); err != nil {
+		return Summary{}, err
+	}
+	if err := ValidateLongBreak(session.LongBreakDuration, session.LongBreakEvery); err != nil {
+		return Summary{}, err
+	}
+	if err := ValidateTask(session.Task); err != nil {
+		return Summary{}, err
+	}
 
-	if continueOnBreak {
-		continueOnBreak = false
-		continuePomodoro(focusDuration, breakDuration)
+	timer := NewTimer(Config{
+		FocusDuration:     time.Duration(session.FocusDuration) * time.Minute,
+		BreakDuration:     time.Duration(session.BreakDuration) * time.Minute,
+		LongBreakDuration: time.Duration(session.LongBreakDuration) * time.Minute,
+		LongBreakEvery:    session.LongBreakEvery,
+		RepeatCount:       session.RepeatCount,
+		ContinueOnBreak:   session.ContinueOnBreak,
+		Task:              session.Task,
+	})
+
+	restore, keys := listenKeys(timer)
+	defer restore()
+	if session.Attach != nil {
+		defer session.Attach(timer)()
 	}
 
+	display := openDisplay(keys, session.Font)
+	defer display.close()
+
+	var summary Summary
+	err := timer.Run(ctx, func(event Event) {
+		if phase, ok := summary.Record(event); ok && session.Record != nil {
+			if err := session.Record(phase); err != nil {
+				display.warn(err)
+			}
+		}
+		display.show(event)
+		if event.Type == EventPhaseEnd {
+			if err := playPhaseEnd(session.Cues, event.State.Phase); err != nil {
+				display.warn(err)
+			}
+		}
+	})
+	return summary, err
+}
+
+// display shows a terminal session as it runs.
+type display interface {
+	show(event Event)
+	warn(err error)
+	close()
 }
 
-func startTimer(duration time.Duration) {
-	for remaining := duration; remaining > 0; remaining -= time.Second {
-		fmt.Printf("\r⏳ %v remaining", remaining.Truncate(time.Second))
-		time.Sleep(time.Second)
+// openDisplay draws the session full-screen when stdout is a terminal and
+// falls back to printing a line per event otherwise.
+func openDisplay(keys bool, font ascii_text.Font) display {
+	if screen := openScreen(os.Stdout, keys, font); screen != nil {
+		return screen
+	}
+	if keys {
+		fmt.Println(keyHints)
 	}
-	fmt.Println("\r✅ Done!                        ")
+	return &lineDisplay{out: os.Stdout}
 }
 
-func continuePomodoro(focusDuration int, breakDuration int) {
-	for {
-		PomodoroTimer(focusDuration, breakDuration, 1, true)
+func playPhaseEnd(cues sound.Cues, phase Phase) error {
+	switch phase {
+	case PhaseFocus:
+		return cues.Focus.Play()
+	case PhaseBreak:
+		return cues.Break.Play()
+	case PhaseLongBreak:
+		return cues.LongBreak.Play()
 	}
+	return nil
+}
+
+// lineDisplay prints the session for output that is not a terminal, such
+// as a pipe or a log file: a plain line for every change, and the time left
+// once a minute while a phase runs.
+type lineDisplay struct {
+	out io.Writer
+	// minutes is the last count of minutes left that was printed.
+	minutes int
+}
+
+func (d *lineDisplay) warn(err error) {
+	fmt.Fprintf(d.out, "⚠️  %v\n", err)
 }
 
-func repeatPomodoro(focusDuration int, breakDuration int, repeatCount int) {
-	for i := 1; i <= repeatCount; i++ {
-		fmt.Printf("🔁 Starting Pomodoro session %d/%d...\n", i, repeatCount)
-		PomodoroTimer(focusDuration, breakDuration, 1, false)
-		if i < repeatCount {
-			fmt.Println("🌟 Get ready for the next Pomodoro!")
-		} else if i == repeatCount {
-			fmt.Println("🎉 All Pomodoros completed! Great job!")
-			fmt.Println("🍅 Time for a well-deserved long break!")
+func (d *lineDisplay) close() {}
+
+func (d *lineDisplay) show(event Event) {
+	state := event.State
+	remaining := state.Remaining.Round(time.Second)
+
+	switch event.Type {
+	case EventPhaseStart:
+		switch state.Phase {
+		case PhaseFocus:
+			if state.RepeatCount > 1 {
+				fmt.Fprintf(d.out, "🔁 Starting Pomodoro session %d/%d...\n", state.Cycle, state.RepeatCount)
+			}
+			fmt.Fprintf(d.out, "🧭 Aragomodoro begins! Focus for %d minutes.\n", int(state.Duration/time.Minute))
+			if state.Task != "" {
+				fmt.Fprintf(d.out, "📝 %s\n", state.Task)
+			}
+		case PhaseBreak:
+			fmt.Fprintf(d.out, "🌿 Time for a break! Rest for %d minutes.\n", int(state.Duration/time.Minute))
+		case PhaseLongBreak:
+			fmt.Fprintf(d.out, "🍅 Time for a well-deserved long break! Rest for %d minutes.\n", int(state.Duration/time.Minute))
+		}
+		d.minutes = minutesLeft(state.Remaining)
+	case EventTick:
+		if minutes := minutesLeft(state.Remaining); minutes < d.minutes && state.Remaining > 0 {
+			d.minutes = minutes
+			fmt.Fprintf(d.out, "⏳ %v remaining\n", remaining)
+		}
+	case EventPaused:
+		fmt.Fprintf(d.out, "⏸️  Paused with %v left\n", remaining)
+	case EventResumed:
+		fmt.Fprintf(d.out, "▶️  Resumed with %v left\n", remaining)
+	case EventTaskChanged:
+		fmt.Fprintf(d.out, "📝 %s\n", state.Task)
+	case EventExtended:
+		fmt.Fprintf(d.out, "➕ Extended! %v remaining\n", remaining)
+		d.minutes = minutesLeft(state.Remaining)
+	case EventSkipped:
+		fmt.Fprintln(d.out, "⏭️  Skipped!")
+	case EventPhaseEnd:
+		fmt.Fprintln(d.out, "✅ Done!")
+		if state.Phase != PhaseFocus && state.Cycle < state.RepeatCount {
+			fmt.Fprintln(d.out, "🌟 Get ready for the next Pomodoro!")
+		}
+	case EventCompleted:
+		if state.RepeatCount > 1 {
+			fmt.Fprintln(d.out, "🎉 All Pomodoros completed! Great job!")
 		}
 	}
 }
 
-// func webModePomodoro(focusDuration int, breakDuration int, repeatCount int, continueOnBreak bool, webPort int) {
-
-// }
+// minutesLeft counts the minutes of d that have started, so it drops as
+// each whole minute is reached.
+func minutesLeft(d time.Duration) int {
+	return int((d + time.Minute - 1) / time.Minute)
+}
@@ -1,7 +1,13 @@
 package pomodoro
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateDurations(t *testing.T) {
@@ -67,6 +73,127 @@ func TestValidateDurations_EdgeCases(t *testing.T) {
 	}
 }
 
+func TestValidateLongBreak(t *testing.T) {
+	tests := []struct {
+		name        string
+		duration    int
+		every       int
+		expectError bool
+	}{
+		{"Default", 15, 4, false},
+		{"Disabled", 0, 0, false},
+		{"Maximum", 60, 1, false},
+		{"NegativeDuration", -1, 4, true},
+		{"NegativeInterval", 15, -1, true},
+		{"ExcessiveDuration", 61, 4, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLongBreak(tt.duration, tt.every)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for long break=%d, every=%d", tt.duration, tt.every)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error for long break=%d, every=%d: %v", tt.duration, tt.every, err)
+			}
+		})
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		task        string
+		expectError bool
+	}{
+		{"Empty", "", false},
+		{"Simple", "write RFC", false},
+		{"Maximum", strings.Repeat("á", MaxTaskLength), false},
+		{"TooLong", strings.Repeat("a", MaxTaskLength+1), true},
+		{"Newline", "write\nRFC", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTask(tt.task)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for task %q", tt.task)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error for task %q: %v", tt.task, err)
+			}
+		})
+	}
+}
+
+func TestPomodoroTimer_Validates(t *testing.T) {
+	valid := Session{FocusDuration: 25, BreakDuration: 5, RepeatCount: 1}
+	tests := []struct {
+		name  string
+		apply func(*Session)
+	}{
+		{"Durations", func(s *Session) { s.FocusDuration = 0 }},
+		{"LongBreak", func(s *Session) { s.LongBreakDuration = -1 }},
+		{"Task", func(s *Session) { s.Task = "write\nRFC" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := valid
+			tt.apply(&session)
+			attached := false
+			session.Attach = func(*Timer) func() {
+				attached = true
+				return func() {}
+			}
+			if _, err := PomodoroTimer(context.Background(), session); err == nil {
+				t.Error("Expected an invalid session to be refused")
+			}
+			if attached {
+				t.Error("An invalid session should not start a timer")
+			}
+		})
+	}
+}
+
+// Output that is not a terminal gets whole lines without escapes, with
+// the time left once a minute rather than every second.
+func TestLineDisplay(t *testing.T) {
+	var out bytes.Buffer
+	display := &lineDisplay{out: &out}
+	state := State{Phase: PhaseFocus, Cycle: 1, RepeatCount: 1, Duration: 2 * time.Minute, Remaining: 2 * time.Minute, Task: "write RFC"}
+
+	display.show(Event{Type: EventPhaseStart, State: state})
+	for state.Remaining > 0 {
+		state.Remaining -= time.Second
+		display.show(Event{Type: EventTick, State: state})
+		if state.Remaining == 90*time.Second {
+			display.show(Event{Type: EventPaused, State: state})
+			display.show(Event{Type: EventResumed, State: state})
+		}
+	}
+	display.show(Event{Type: EventPhaseEnd, State: state})
+	display.warn(errors.New("could not save history"))
+
+	got := out.String()
+	if strings.ContainsAny(got, "\r\033") {
+		t.Errorf("Expected plain text, got %q", got)
+	}
+	want := []string{
+		"🧭 Aragomodoro begins! Focus for 2 minutes.",
+		"📝 write RFC",
+		"⏸️  Paused with 1m30s left",
+		"▶️  Resumed with 1m30s left",
+		"⏳ 1m0s remaining",
+		"✅ Done!",
+		"⚠️  could not save history",
+	}
+	if lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n"); !reflect.DeepEqual(lines, want) || !strings.HasSuffix(got, "\n") {
+		t.Errorf("Expected lines %q, got %q", want, got)
+	}
+}
+
 func BenchmarkValidateDurations(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		ValidateDurations(25, 5, 1)
@@ -0,0 +1,77 @@
+package pomodoro
+
+import (
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+// PhaseRecord describes one phase as it was actually run. Planned is the
+// configured length and Actual the time counted down, which leaves out
+// pauses and includes extensions.
+type PhaseRecord struct {
+	Phase   Phase
+	Cycle   int
+	Start   time.Time
+	End     time.Time
+	Outcome string
+	Planned time.Duration
+	Actual  time.Duration
+	Task    string
+}
+
+// CompletedPomodoro reports whether the record is a focus phase that ran
+// to the end.
+func (r PhaseRecord) CompletedPomodoro() bool {
+	return r.Phase == PhaseFocus && r.Outcome == history.OutcomeCompleted
+}
+
+// HistoryEntry converts the record for the history store.
+func (r PhaseRecord) HistoryEntry() history.Entry {
+	return history.Entry{
+		Start:   r.Start,
+		End:     r.End,
+		Type:    string(r.Phase),
+		Outcome: r.Outcome,
+		Planned: r.Planned,
+		Actual:  r.Actual,
+		Label:   r.Task,
+	}
+}
+
+// PhaseTracker follows a timer's events and produces a record whenever a
+// phase finishes, is skipped or is stopped.
+type PhaseTracker struct {
+	start   time.Time
+	planned time.Duration
+}
+
+func (p *PhaseTracker) Track(event Event) (PhaseRecord, bool) {
+	var outcome string
+	switch event.Type {
+	case EventPhaseStart:
+		p.start = event.At
+		p.planned = event.State.Duration
+		return PhaseRecord{}, false
+	case EventPhaseEnd:
+		outcome = history.OutcomeCompleted
+	case EventSkipped:
+		outcome = history.OutcomeSkipped
+	case EventStopped:
+		outcome = history.OutcomeStopped
+	default:
+		return PhaseRecord{}, false
+	}
+
+	state := event.State
+	return PhaseRecord{
+		Phase:   state.Phase,
+		Cycle:   state.Cycle,
+		Start:   p.start,
+		End:     event.At,
+		Outcome: outcome,
+		Planned: p.planned,
+		Actual:  state.Duration - state.Remaining,
+		Task:    state.Task,
+	}, true
+}
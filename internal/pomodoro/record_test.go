@@ -0,0 +1,94 @@
+package pomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+func TestPhaseTracker_Track(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 3 * time.Second,
+		BreakDuration: 2 * time.Second,
+		RepeatCount:   1,
+	})
+
+	// Focus runs with a one second pause and is extended by a second; the
+	// break is skipped halfway through its first second.
+	advance(fake, 1)
+	timer.Pause()
+	fake.Advance(time.Second)
+	timer.Resume()
+	timer.Extend(time.Second)
+	advance(fake, 3)
+	fake.BlockUntil(1)
+	fake.Advance(500 * time.Millisecond)
+	timer.Skip()
+	<-done
+
+	var records []PhaseRecord
+	var tracker PhaseTracker
+	for event := range events {
+		if record, ok := tracker.Track(event); ok {
+			records = append(records, record)
+		}
+	}
+
+	expected := []PhaseRecord{
+		{Phase: PhaseFocus, Cycle: 1, Start: epoch, End: epoch.Add(5 * time.Second), Outcome: history.OutcomeCompleted, Planned: 3 * time.Second, Actual: 4 * time.Second},
+		{Phase: PhaseBreak, Cycle: 1, Start: epoch.Add(5 * time.Second), End: epoch.Add(5500 * time.Millisecond), Outcome: history.OutcomeSkipped, Planned: 2 * time.Second, Actual: 500 * time.Millisecond},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %+v", len(expected), records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("Record %d: expected %+v, got %+v", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestPhaseRecord_HistoryEntry(t *testing.T) {
+	record := PhaseRecord{
+		Phase:   PhaseLongBreak,
+		Cycle:   4,
+		Start:   epoch,
+		End:     epoch.Add(15 * time.Minute),
+		Outcome: history.OutcomeCompleted,
+		Planned: 15 * time.Minute,
+		Actual:  15 * time.Minute,
+		Task:    "chapter 3",
+	}
+
+	entry := record.HistoryEntry()
+	expected := history.Entry{
+		Start:   epoch,
+		End:     epoch.Add(15 * time.Minute),
+		Type:    "long_break",
+		Outcome: history.OutcomeCompleted,
+		Planned: 15 * time.Minute,
+		Actual:  15 * time.Minute,
+		Label:   "chapter 3",
+	}
+	if entry != expected {
+		t.Errorf("Expected %+v, got %+v", expected, entry)
+	}
+}
+
+func TestPhaseRecord_CompletedPomodoro(t *testing.T) {
+	tests := []struct {
+		record PhaseRecord
+		want   bool
+	}{
+		{PhaseRecord{Phase: PhaseFocus, Outcome: history.OutcomeCompleted}, true},
+		{PhaseRecord{Phase: PhaseFocus, Outcome: history.OutcomeSkipped}, false},
+		{PhaseRecord{Phase: PhaseFocus, Outcome: history.OutcomeStopped}, false},
+		{PhaseRecord{Phase: PhaseBreak, Outcome: history.OutcomeCompleted}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.record.CompletedPomodoro(); got != tt.want {
+			t.Errorf("%s %s: CompletedPomodoro() = %v, want %v", tt.record.Phase, tt.record.Outcome, got, tt.want)
+		}
+	}
+}
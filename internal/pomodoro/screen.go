@@ -0,0 +1,294 @@
+package pomodoro
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/ascii_text"
+	"github.com/aureliomalheiros/aragomodoro/internal/terminal"
+)
+
+const (
+	enterAltScreen = "\033[?1049h"
+	leaveAltScreen = "\033[?1049l"
+	hideCursor     = "\033[?25l"
+	showCursor     = "\033[?25h"
+	cursorHome     = "\033[H"
+	clearToEOL     = "\033[K"
+	clearToEnd     = "\033[J"
+)
+
+// noticeFor is how long a notice such as "Skipped" stays on screen.
+const noticeFor = 5 * time.Second
+
+// screen draws the session full-screen: a big countdown, a progress bar,
+// the phase and cycle, the task and the keys. It redraws in place on every
+// event and when the terminal is resized.
+type screen struct {
+	out    *os.File
+	keys   bool
+	font   ascii_text.Font
+	resize chan os.Signal
+	done   chan struct{}
+
+	mu       sync.Mutex
+	width    int
+	height   int
+	state    State
+	notice   string
+	noticeAt time.Time
+	closed   bool
+}
+
+// openScreen takes over out, switching to the alternate screen so the
+// shell's scrollback is left alone. It returns nil when out is not a
+// terminal.
+func openScreen(out *os.File, keys bool, font ascii_text.Font) *screen {
+	fd := int(out.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil
+	}
+	width, height, err := terminal.Size(fd)
+	if err != nil {
+		return nil
+	}
+
+	s := &screen{
+		out:    out,
+		keys:   keys,
+		font:   font,
+		resize: make(chan os.Signal, 1),
+		done:   make(chan struct{}),
+		width:  width,
+		height: height,
+	}
+	fmt.Fprint(out, enterAltScreen+hideCursor)
+	terminal.NotifyResize(s.resize)
+	go s.watchResize(fd)
+	return s
+}
+
+func (s *screen) watchResize(fd int) {
+	for {
+		select {
+		case <-s.resize:
+			width, height, err := terminal.Size(fd)
+			if err != nil {
+				continue
+			}
+			s.mu.Lock()
+			s.width, s.height = width, height
+			s.draw()
+			s.mu.Unlock()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *screen) show(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = event.State
+	switch event.Type {
+	case EventExtended:
+		s.setNotice(event.At, "➕ Extended!")
+	case EventSkipped:
+		s.setNotice(event.At, "⏭️  Skipped!")
+	case EventPhaseEnd:
+		if event.State.Phase == PhaseFocus {
+			s.setNotice(event.At, "✅ Done! Time to rest.")
+		} else if event.State.Cycle < event.State.RepeatCount {
+			s.setNotice(event.At, "🌟 Get ready for the next Pomodoro!")
+		}
+	}
+	if !s.noticeAt.IsZero() && event.At.Sub(s.noticeAt) >= noticeFor {
+		s.notice = ""
+	}
+	s.draw()
+}
+
+func (s *screen) warn(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Warnings stay up until the next notice replaces them.
+	s.notice, s.noticeAt = "⚠️  "+err.Error(), time.Time{}
+	s.draw()
+}
+
+func (s *screen) setNotice(at time.Time, notice string) {
+	s.notice, s.noticeAt = notice, at
+}
+
+// close gives the terminal back, leaving a line behind for a session that
+// ran to the end.
+func (s *screen) close() {
+	signal.Stop(s.resize)
+	close(s.done)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	fmt.Fprint(s.out, showCursor+leaveAltScreen)
+	if s.state.Phase == PhaseCompleted && s.state.RepeatCount > 1 {
+		fmt.Fprintln(s.out, "🎉 All Pomodoros completed! Great job!")
+	}
+}
+
+// draw must be called with mu held.
+func (s *screen) draw() {
+	if s.closed || s.state.Phase == "" {
+		return
+	}
+	fmt.Fprint(s.out, render(s.font, s.state, s.notice, s.keys, s.width, s.height))
+}
+
+var phaseTitles = map[Phase]string{
+	PhaseFocus:     "🧭 Focus",
+	PhaseBreak:     "🌿 Break",
+	PhaseLongBreak: "🍅 Long break",
+	PhaseCompleted: "🎉 Completed",
+}
+
+// phaseWords are drawn in the block font above the countdown when the
+// terminal has room for them.
+var phaseWords = map[Phase]string{
+	PhaseFocus:     "FOCUS",
+	PhaseBreak:     "BREAK",
+	PhaseLongBreak: "LONG BREAK",
+}
+
+// render builds a frame for a width by height terminal, centered both
+// ways, with the phase and countdown in font. It overwrites the previous
+// frame line by line instead of clearing the screen first, so redraws do
+// not flicker.
+func render(font ascii_text.Font, state State, notice string, keys bool, width, height int) string {
+	lines := screenLines(font, state, notice, keys, width, height)
+
+	var b strings.Builder
+	b.WriteString(cursorHome)
+	row := 0
+	newline := func() {
+		// The last row gets no newline, or the terminal would scroll.
+		if row < height-1 {
+			b.WriteString("\n")
+		}
+		row++
+	}
+	for top := (height - len(lines)) / 2; row < top; {
+		b.WriteString(clearToEOL)
+		newline()
+	}
+	for _, line := range lines {
+		if row >= height {
+			break
+		}
+		if line != "" {
+			line = fit(line, width)
+			b.WriteString(strings.Repeat(" ", (width-displayWidth(line))/2))
+			b.WriteString(line)
+		}
+		b.WriteString(clearToEOL)
+		newline()
+	}
+	b.WriteString(clearToEnd)
+	return b.String()
+}
+
+func screenLines(font ascii_text.Font, state State, notice string, keys bool, width, height int) []string {
+	header := phaseTitles[state.Phase]
+	if state.Phase != PhaseCompleted {
+		header += fmt.Sprintf(" · Pomodoro %d of %d", state.Cycle, state.RepeatCount)
+	}
+	if state.Paused {
+		header += " · ⏸️  Paused"
+	}
+	var lines []string
+	if word := phaseWords[state.Phase]; word != "" && font.Width(word) <= width && 2*font.Height()+12 <= height {
+		lines = append(font.Lines(word), "")
+	}
+	lines = append(lines, header, "")
+
+	clock := formatClock(state.Remaining)
+	// The big clock needs room for itself and the lines around it.
+	if font.Width(clock) <= width && font.Height()+10 <= height {
+		lines = append(lines, font.Lines(clock)...)
+	} else {
+		lines = append(lines, clock)
+	}
+
+	lines = append(lines, "", progressBar(state, min(width-5, 50)))
+	if state.Task != "" {
+		lines = append(lines, "", "📝 "+state.Task)
+	}
+	if notice != "" {
+		lines = append(lines, "", notice)
+	}
+	if keys {
+		lines = append(lines, "", keyHints)
+	}
+	return lines
+}
+
+// formatClock formats d as mm:ss, or h:mm:ss from an hour up.
+func formatClock(d time.Duration) string {
+	seconds := int(d.Round(time.Second) / time.Second)
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// progressBar shows how much of the phase has gone by, followed by the
+// percentage.
+func progressBar(state State, width int) string {
+	percent := 0
+	if state.Duration > 0 {
+		percent = int((state.Duration - state.Remaining) * 100 / state.Duration)
+	}
+	if state.Phase == PhaseCompleted {
+		percent = 100
+	}
+	percent = max(0, min(percent, 100))
+
+	width = max(width, 10)
+	filled := width * percent / 100
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + fmt.Sprintf(" %3d%%", percent)
+}
+
+// fit cuts line down to width columns, marking the cut with an ellipsis.
+func fit(line string, width int) string {
+	if displayWidth(line) <= width {
+		return line
+	}
+	runes := []rune(line)
+	for len(runes) > 0 && displayWidth(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// displayWidth estimates how many columns s takes, counting emoji as two
+// and joiners and variation selectors as none.
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case r == 0xFE0F || r == 0x200D:
+		case r >= 0x1F000,
+			r >= 0x2300 && r <= 0x23FF,
+			r >= 0x2600 && r <= 0x27BF,
+			r >= 0x2B00 && r <= 0x2BFF:
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
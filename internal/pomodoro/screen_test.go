@@ -0,0 +1,119 @@
+package pomodoro
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/ascii_text"
+)
+
+func TestRender(t *testing.T) {
+	state := State{
+		Phase:       PhaseFocus,
+		Cycle:       2,
+		RepeatCount: 4,
+		Duration:    25 * time.Minute,
+		Remaining:   12*time.Minute + 30*time.Second,
+		Task:        "write report",
+	}
+	frame := render(ascii_text.Block, state, "⏭️  Skipped!", true, 100, 30)
+
+	for _, want := range []string{
+		ascii_text.Lines("FOCUS")[0],
+		"🧭 Focus · Pomodoro 2 of 4",
+		ascii_text.Lines("12:30")[3],
+		" 50%",
+		"📝 write report",
+		"Skipped!",
+		keyHints,
+	} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame is missing %q:\n%s", want, frame)
+		}
+	}
+	if !strings.HasPrefix(frame, cursorHome) || !strings.HasSuffix(frame, clearToEnd) {
+		t.Errorf("frame should redraw in place: %q", frame)
+	}
+	if rows := strings.Count(frame, "\n"); rows > 29 {
+		t.Errorf("frame has %d newlines, which scrolls a 30-row terminal", rows)
+	}
+	if !strings.HasPrefix(frame, cursorHome+strings.Repeat(clearToEOL+"\n", 2)) {
+		t.Errorf("frame should be centered vertically: %q", frame[:40])
+	}
+}
+
+func TestRender_Short(t *testing.T) {
+	state := State{Phase: PhaseLongBreak, Cycle: 4, RepeatCount: 4, Duration: 15 * time.Minute, Remaining: 15 * time.Minute}
+	frame := render(ascii_text.Block, state, "", false, 100, 24)
+
+	if strings.Contains(frame, ascii_text.Lines("LONG BREAK")[0]) {
+		t.Errorf("a short terminal should leave out the phase word:\n%s", frame)
+	}
+	if !strings.Contains(frame, ascii_text.Lines("15:00")[0]) || !strings.Contains(frame, "🍅 Long break") {
+		t.Errorf("frame should still show the clock and phase:\n%s", frame)
+	}
+}
+
+func TestRender_Narrow(t *testing.T) {
+	state := State{Phase: PhaseBreak, Cycle: 1, RepeatCount: 1, Duration: 5 * time.Minute, Remaining: 65 * time.Second, Paused: true}
+	frame := render(ascii_text.Block, state, "", false, 40, 12)
+
+	if !strings.Contains(frame, "01:05") {
+		t.Errorf("narrow frame should show the plain clock:\n%s", frame)
+	}
+	if strings.Contains(frame, "▓") {
+		t.Errorf("narrow frame should not draw big digits:\n%s", frame)
+	}
+	if !strings.Contains(frame, "Paused") {
+		t.Errorf("frame should show the pause:\n%s", frame)
+	}
+	if strings.Contains(frame, keyHints) {
+		t.Error("frame should leave out the keys when they are not read")
+	}
+}
+
+func TestRender_Font(t *testing.T) {
+	font, err := ascii_text.LoadFont("mini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := State{Phase: PhaseFocus, Cycle: 1, RepeatCount: 1, Duration: 25 * time.Minute, Remaining: 25 * time.Minute}
+	frame := render(font, state, "", false, 60, 20)
+
+	for _, want := range append(font.Lines("FOCUS"), font.Lines("25:00")...) {
+		if !strings.Contains(frame, strings.TrimRight(want, " ")) {
+			t.Errorf("frame is missing %q:\n%s", want, frame)
+		}
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                                     "00:00",
+		59*time.Second + 600*time.Millisecond: "01:00",
+		25 * time.Minute:                      "25:00",
+		75 * time.Minute:                      "1:15:00",
+	}
+	for d, want := range tests {
+		if got := formatClock(d); got != want {
+			t.Errorf("formatClock(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	state := State{Phase: PhaseFocus, Duration: 10 * time.Minute, Remaining: 7 * time.Minute}
+	if got, want := progressBar(state, 10), "███░░░░░░░  30%"; got != want {
+		t.Errorf("progressBar = %q, want %q", got, want)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("📝 a long task", 8); got != "📝 a lo…" {
+		t.Errorf("fit = %q", got)
+	}
+	if got := fit("short", 8); got != "short" {
+		t.Errorf("fit = %q", got)
+	}
+}
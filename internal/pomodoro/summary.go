@@ -0,0 +1,55 @@
+package pomodoro
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+// Summary tallies a session from its timer events so it can be reported
+// when the session ends, including a phase that was cut short.
+type Summary struct {
+	CompletedPomodoros int
+	FocusTime          time.Duration
+	BreakTime          time.Duration
+	Partial            *PhaseRecord
+
+	tracker PhaseTracker
+}
+
+// Record adds event to the summary and returns the phase it finished, if
+// any.
+func (s *Summary) Record(event Event) (PhaseRecord, bool) {
+	record, ok := s.tracker.Track(event)
+	if !ok {
+		return record, false
+	}
+
+	s.add(record)
+	if record.CompletedPomodoro() {
+		s.CompletedPomodoros++
+	}
+	if record.Outcome == history.OutcomeStopped {
+		s.Partial = &record
+	}
+	return record, true
+}
+
+func (s *Summary) add(record PhaseRecord) {
+	if record.Phase == PhaseFocus {
+		s.FocusTime += record.Actual
+	} else {
+		s.BreakTime += record.Actual
+	}
+}
+
+func PrintSummary(summary Summary) {
+	fmt.Println("\n🛑 Session interrupted.")
+	fmt.Printf("🍅 Completed pomodoros: %d\n", summary.CompletedPomodoros)
+	fmt.Printf("🧭 Focus time: %v\n", summary.FocusTime.Round(time.Second))
+	fmt.Printf("🌿 Break time: %v\n", summary.BreakTime.Round(time.Second))
+	if p := summary.Partial; p != nil && p.Phase == PhaseFocus && p.Actual > 0 {
+		fmt.Printf("✂️  Partial pomodoro: %v of %v\n", p.Actual.Round(time.Second), p.Planned.Round(time.Second))
+	}
+}
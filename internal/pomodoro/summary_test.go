@@ -0,0 +1,70 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+func TestSummary_Record(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timer, fake, events, done := startFakeTimerContext(ctx, Config{
+		FocusDuration: 3 * time.Second,
+		BreakDuration: 2 * time.Second,
+		RepeatCount:   3,
+	})
+
+	// One full pomodoro, a second of break that gets skipped, then two
+	// seconds into the next focus phase
+	advance(fake, 3+1)
+	timer.Skip()
+	advance(fake, 2)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	var summary Summary
+	for event := range events {
+		summary.Record(event)
+	}
+
+	if summary.CompletedPomodoros != 1 {
+		t.Errorf("Expected 1 completed pomodoro, got %d", summary.CompletedPomodoros)
+	}
+	if summary.FocusTime != 5*time.Second {
+		t.Errorf("Expected 5s of focus, got %v", summary.FocusTime)
+	}
+	if summary.BreakTime != time.Second {
+		t.Errorf("Expected 1s of break, got %v", summary.BreakTime)
+	}
+	expected := PhaseRecord{
+		Phase:   PhaseFocus,
+		Cycle:   2,
+		Start:   epoch.Add(4 * time.Second),
+		End:     epoch.Add(6 * time.Second),
+		Outcome: history.OutcomeStopped,
+		Planned: 3 * time.Second,
+		Actual:  2 * time.Second,
+	}
+	if summary.Partial == nil || *summary.Partial != expected {
+		t.Errorf("Expected partial %+v, got %+v", expected, summary.Partial)
+	}
+}
+
+func TestSummary_PlannedExcludesExtensions(t *testing.T) {
+	var summary Summary
+	summary.Record(Event{Type: EventPhaseStart, State: State{Phase: PhaseFocus, Cycle: 1, Duration: 25 * time.Minute, Remaining: 25 * time.Minute}})
+	summary.Record(Event{Type: EventExtended, State: State{Phase: PhaseFocus, Cycle: 1, Duration: 30 * time.Minute, Remaining: 30 * time.Minute}})
+	summary.Record(Event{Type: EventPhaseEnd, State: State{Phase: PhaseFocus, Cycle: 1, Duration: 30 * time.Minute}})
+
+	if summary.FocusTime != 30*time.Minute {
+		t.Errorf("Expected 30m of focus, got %v", summary.FocusTime)
+	}
+	if summary.Partial != nil {
+		t.Errorf("Expected no partial phase, got %+v", summary.Partial)
+	}
+}
@@ -0,0 +1,367 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+)
+
+var ErrStopped = errors.New("timer stopped")
+
+type Phase string
+
+const (
+	PhaseFocus     Phase = "focus"
+	PhaseBreak     Phase = "break"
+	PhaseLongBreak Phase = "long_break"
+	PhaseCompleted Phase = "completed"
+)
+
+type EventType int
+
+const (
+	EventPhaseStart EventType = iota
+	EventTick
+	EventPhaseEnd
+	EventCompleted
+	EventStopped
+	EventPaused
+	EventResumed
+	EventSkipped
+	EventExtended
+	EventTaskChanged
+)
+
+type Config struct {
+	FocusDuration     time.Duration
+	BreakDuration     time.Duration
+	LongBreakDuration time.Duration
+	LongBreakEvery    int
+	RepeatCount       int
+	ContinueOnBreak   bool
+	Task              string
+	// Clock drives the countdown; nil means the real clock.
+	Clock clock.Clock
+}
+
+type State struct {
+	Phase       Phase
+	Cycle       int
+	RepeatCount int
+	Duration    time.Duration
+	Remaining   time.Duration
+	// EndsAt is the wall-clock time the phase ends; zero while paused.
+	EndsAt time.Time
+	Paused bool
+	Task   string
+}
+
+type Event struct {
+	Type  EventType
+	State State
+	// At is when the event happened; a phase starts and ends on schedule.
+	At time.Time
+}
+
+// Timer is the focus/break state machine shared by the terminal and web
+// front ends. Every focus phase is followed by a break, which is a long
+// break after each LongBreakEvery pomodoros; once RepeatCount pomodoros are
+// done the timer completes, or starts over when ContinueOnBreak is set.
+type Timer struct {
+	config   Config
+	clock    clock.Clock
+	mu       sync.RWMutex
+	state    State
+	control  chan command
+	done     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type commandKind int
+
+const (
+	commandPause commandKind = iota
+	commandResume
+	commandSkip
+	commandExtend
+	commandSetTask
+)
+
+// command is a request sent to the running phase loop. handled is closed
+// once the loop has applied it and reported the resulting event.
+type command struct {
+	kind     commandKind
+	duration time.Duration
+	task     string
+	handled  chan struct{}
+}
+
+func NewTimer(config Config) *Timer {
+	c := config.Clock
+	if c == nil {
+		c = clock.Real()
+	}
+
+	return &Timer{
+		config: config,
+		clock:  c,
+		state: State{
+			Phase:       PhaseFocus,
+			Cycle:       1,
+			RepeatCount: config.RepeatCount,
+			Duration:    config.FocusDuration,
+			Remaining:   config.FocusDuration,
+			Task:        config.Task,
+		},
+		control: make(chan command),
+		done:    make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+}
+
+func (t *Timer) State() State {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.state
+}
+
+func (t *Timer) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+// Pause freezes the countdown and Resume sets a new deadline from the exact
+// time that was left. Both return once the change has been reported
+// through onEvent, so they must not be called from inside the callback.
+func (t *Timer) Pause() {
+	t.send(command{kind: commandPause})
+}
+
+func (t *Timer) Resume() {
+	t.send(command{kind: commandResume})
+}
+
+// Skip ends the current phase right away and moves on to the next one.
+func (t *Timer) Skip() {
+	t.send(command{kind: commandSkip})
+}
+
+// Extend adds d, truncated to whole seconds, to the current phase.
+func (t *Timer) Extend(d time.Duration) {
+	t.send(command{kind: commandExtend, duration: d.Truncate(time.Second)})
+}
+
+// SetTask changes what the session is working on; phases that end from
+// now on are recorded under the new task.
+func (t *Timer) SetTask(task string) {
+	t.send(command{kind: commandSetTask, task: task})
+}
+
+func (t *Timer) send(cmd command) {
+	cmd.handled = make(chan struct{})
+	select {
+	case t.control <- cmd:
+	case <-t.done:
+		return
+	}
+	<-cmd.handled
+}
+
+// Run drives the timer through its phases, calling onEvent on every phase
+// change and tick. It blocks until the timer completes, returning nil, or
+// until it is stopped or ctx is done, returning ErrStopped or ctx.Err().
+func (t *Timer) Run(ctx context.Context, onEvent func(Event)) error {
+	defer close(t.done)
+
+	// Each phase starts when the previous one was due to end, so time spent
+	// in onEvent (or asleep) is not added on top of the schedule.
+	startedAt := t.now()
+	pomodoros := 0
+	for {
+		for cycle := 1; cycle <= t.config.RepeatCount; cycle++ {
+			var err error
+			if startedAt, err = t.runPhase(ctx, PhaseFocus, cycle, t.config.FocusDuration, startedAt, onEvent); err != nil {
+				return err
+			}
+			pomodoros++
+
+			phase, duration := t.breakAfter(pomodoros)
+			if startedAt, err = t.runPhase(ctx, phase, cycle, duration, startedAt, onEvent); err != nil {
+				return err
+			}
+		}
+		if !t.config.ContinueOnBreak {
+			break
+		}
+	}
+
+	t.mu.Lock()
+	t.state.Phase = PhaseCompleted
+	t.state.Remaining = 0
+	t.state.EndsAt = time.Time{}
+	t.mu.Unlock()
+	onEvent(Event{Type: EventCompleted, State: t.State(), At: t.now()})
+	return nil
+}
+
+// breakAfter picks the break that follows the given number of pomodoros.
+func (t *Timer) breakAfter(pomodoros int) (Phase, time.Duration) {
+	every := t.config.LongBreakEvery
+	if every > 0 && t.config.LongBreakDuration > 0 && pomodoros%every == 0 {
+		return PhaseLongBreak, t.config.LongBreakDuration
+	}
+	return PhaseBreak, t.config.BreakDuration
+}
+
+// now reads the clock without its monotonic reading. Deadlines are then
+// compared on the wall clock, which keeps running while the system is
+// suspended, so a phase that ended during suspend ends on wake-up.
+func (t *Timer) now() time.Time {
+	return t.clock.Now().Round(0)
+}
+
+// runPhase counts a phase down from startedAt and returns when it was due to
+// end, which is where the next phase starts. Remaining time is always
+// derived from the deadline; ticks only decide when to report it.
+func (t *Timer) runPhase(ctx context.Context, phase Phase, cycle int, duration time.Duration, startedAt time.Time, onEvent func(Event)) (time.Time, error) {
+	t.mu.Lock()
+	paused := t.state.Paused
+	task := t.state.Task
+	deadline := startedAt.Add(duration)
+	remaining := duration
+	if !paused {
+		remaining = clampRemaining(deadline.Sub(t.now()))
+	}
+	t.state = State{
+		Phase:       phase,
+		Cycle:       cycle,
+		RepeatCount: t.config.RepeatCount,
+		Duration:    duration,
+		Remaining:   remaining,
+		Paused:      paused,
+		Task:        task,
+	}
+	if !paused {
+		t.state.EndsAt = deadline
+	}
+	t.mu.Unlock()
+	onEvent(Event{Type: EventPhaseStart, State: t.State(), At: startedAt})
+
+	tick := t.clock.NewTimer(untilNextTick(remaining))
+	defer tick.Stop()
+	if paused {
+		stopTimer(tick)
+	}
+
+	for paused || remaining > 0 {
+		select {
+		case <-t.stop:
+			t.emit(onEvent, EventStopped, deadline, paused)
+			return time.Time{}, ErrStopped
+		case <-ctx.Done():
+			t.emit(onEvent, EventStopped, deadline, paused)
+			return time.Time{}, ctx.Err()
+		case cmd := <-t.control:
+			switch {
+			case cmd.kind == commandPause && !paused:
+				stopTimer(tick)
+				remaining = clampRemaining(deadline.Sub(t.now()))
+				paused = true
+				t.update(remaining, time.Time{}, paused)
+				onEvent(Event{Type: EventPaused, State: t.State(), At: t.now()})
+			case cmd.kind == commandResume && paused:
+				deadline = t.now().Add(remaining)
+				tick.Reset(untilNextTick(remaining))
+				paused = false
+				t.update(remaining, deadline, paused)
+				onEvent(Event{Type: EventResumed, State: t.State(), At: t.now()})
+			case cmd.kind == commandSkip:
+				at := t.emit(onEvent, EventSkipped, deadline, paused)
+				close(cmd.handled)
+				return at, nil
+			case cmd.kind == commandExtend && cmd.duration > 0:
+				if paused {
+					remaining += cmd.duration
+					t.update(remaining, time.Time{}, paused)
+				} else {
+					deadline = deadline.Add(cmd.duration)
+					remaining = clampRemaining(deadline.Sub(t.now()))
+					stopTimer(tick)
+					tick.Reset(untilNextTick(remaining))
+					t.update(remaining, deadline, paused)
+				}
+				t.mu.Lock()
+				t.state.Duration += cmd.duration
+				t.mu.Unlock()
+				onEvent(Event{Type: EventExtended, State: t.State(), At: t.now()})
+			case cmd.kind == commandSetTask:
+				t.mu.Lock()
+				t.state.Task = cmd.task
+				t.mu.Unlock()
+				onEvent(Event{Type: EventTaskChanged, State: t.State(), At: t.now()})
+			}
+			close(cmd.handled)
+			continue
+		case <-tick.C():
+		}
+
+		remaining = clampRemaining(deadline.Sub(t.now()))
+		t.update(remaining, deadline, paused)
+		onEvent(Event{Type: EventTick, State: t.State(), At: t.now()})
+		if remaining > 0 {
+			tick.Reset(untilNextTick(remaining))
+		}
+	}
+
+	onEvent(Event{Type: EventPhaseEnd, State: t.State(), At: deadline})
+	return deadline, nil
+}
+
+// emit reports a phase being cut short, first bringing the remaining time
+// up to date since it may be most of a tick old. It returns when that was.
+func (t *Timer) emit(onEvent func(Event), eventType EventType, deadline time.Time, paused bool) time.Time {
+	now := t.now()
+	if !paused {
+		t.update(clampRemaining(deadline.Sub(now)), deadline, paused)
+	}
+	onEvent(Event{Type: eventType, State: t.State(), At: now})
+	return now
+}
+
+func (t *Timer) update(remaining time.Duration, endsAt time.Time, paused bool) {
+	t.mu.Lock()
+	t.state.Remaining = remaining
+	t.state.EndsAt = endsAt
+	t.state.Paused = paused
+	t.mu.Unlock()
+}
+
+// untilNextTick returns how long until remaining reaches the next whole
+// second, so ticks report 24m59s, 24m58s and so on.
+func untilNextTick(remaining time.Duration) time.Duration {
+	if r := remaining % time.Second; r > 0 {
+		return r
+	}
+	return time.Second
+}
+
+func clampRemaining(remaining time.Duration) time.Duration {
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func stopTimer(timer clock.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C():
+		default:
+		}
+	}
+}
@@ -0,0 +1,502 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+)
+
+var epoch = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// startFakeTimer runs a timer driven by a fake clock on its own goroutine.
+// Every event is sent on the returned channel, which is closed once Run has
+// returned and its result sent on done.
+func startFakeTimer(config Config) (*Timer, *clock.Fake, <-chan Event, <-chan error) {
+	return startFakeTimerContext(context.Background(), config)
+}
+
+func startFakeTimerContext(ctx context.Context, config Config) (*Timer, *clock.Fake, <-chan Event, <-chan error) {
+	fake := clock.NewFake(epoch)
+	config.Clock = fake
+	timer := NewTimer(config)
+
+	events := make(chan Event, 1024)
+	done := make(chan error, 1)
+	go func() {
+		done <- timer.Run(ctx, func(event Event) {
+			events <- event
+		})
+		close(events)
+	}()
+
+	return timer, fake, events, done
+}
+
+// advance moves the fake clock one second at a time, waiting for the timer
+// to arm its next tick before each step.
+func advance(fake *clock.Fake, seconds int) {
+	for i := 0; i < seconds; i++ {
+		fake.BlockUntil(1)
+		fake.Advance(time.Second)
+	}
+}
+
+// nextEvent skips events until one of the given type arrives.
+func nextEvent(t *testing.T, events <-chan Event, eventType EventType) Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("Timer finished before event %v", eventType)
+			}
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for event %v", eventType)
+		}
+	}
+}
+
+type phaseStart struct {
+	phase Phase
+	cycle int
+}
+
+func assertPhaseStarts(t *testing.T, got, expected []phaseStart) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected phases %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Phase %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestNewTimer_InitialState(t *testing.T) {
+	timer := NewTimer(Config{
+		FocusDuration: 25 * time.Minute,
+		BreakDuration: 5 * time.Minute,
+		RepeatCount:   4,
+	})
+
+	state := timer.State()
+	if state.Phase != PhaseFocus {
+		t.Errorf("Expected phase %q, got %q", PhaseFocus, state.Phase)
+	}
+	if state.Cycle != 1 {
+		t.Errorf("Expected cycle 1, got %d", state.Cycle)
+	}
+	if state.RepeatCount != 4 {
+		t.Errorf("Expected repeat count 4, got %d", state.RepeatCount)
+	}
+	if state.Remaining != 25*time.Minute {
+		t.Errorf("Expected remaining 25m, got %v", state.Remaining)
+	}
+}
+
+func TestTimer_StopBeforeRun(t *testing.T) {
+	timer := NewTimer(Config{
+		FocusDuration: 25 * time.Minute,
+		BreakDuration: 5 * time.Minute,
+		RepeatCount:   1,
+	})
+	timer.Stop()
+	// Stopping twice must not panic
+	timer.Stop()
+
+	var events []EventType
+	err := timer.Run(context.Background(), func(event Event) {
+		events = append(events, event.Type)
+	})
+
+	if !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+	expected := []EventType{EventPhaseStart, EventStopped}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Event %d: expected %v, got %v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestTimer_FullCycle(t *testing.T) {
+	_, fake, events, done := startFakeTimer(Config{
+		FocusDuration:     3 * time.Second,
+		BreakDuration:     2 * time.Second,
+		LongBreakDuration: 4 * time.Second,
+		LongBreakEvery:    2,
+		RepeatCount:       2,
+	})
+
+	// focus, break, focus, long break
+	advance(fake, 3+2+3+4)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Timer should complete after all phases ran, got %v", err)
+	}
+
+	var ticks, ends int
+	var last Event
+	var starts []phaseStart
+	for event := range events {
+		switch event.Type {
+		case EventPhaseStart:
+			starts = append(starts, phaseStart{event.State.Phase, event.State.Cycle})
+		case EventTick:
+			ticks++
+		case EventPhaseEnd:
+			ends++
+		}
+		last = event
+	}
+
+	assertPhaseStarts(t, starts, []phaseStart{
+		{PhaseFocus, 1},
+		{PhaseBreak, 1},
+		{PhaseFocus, 2},
+		{PhaseLongBreak, 2},
+	})
+	if ticks != 12 {
+		t.Errorf("Expected 12 ticks, got %d", ticks)
+	}
+	if ends != 4 {
+		t.Errorf("Expected 4 phase ends, got %d", ends)
+	}
+	if last.Type != EventCompleted || last.State.Phase != PhaseCompleted {
+		t.Errorf("Expected a final completed event, got %+v", last)
+	}
+}
+
+func TestTimer_StopMidPhase(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 3 * time.Second,
+		BreakDuration: 2 * time.Second,
+		RepeatCount:   1,
+	})
+
+	advance(fake, 2)
+	nextEvent(t, events, EventTick)
+	nextEvent(t, events, EventTick)
+	timer.Stop()
+
+	if err := <-done; !errors.Is(err, ErrStopped) {
+		t.Fatalf("Expected ErrStopped, got %v", err)
+	}
+	stopped := nextEvent(t, events, EventStopped)
+	if stopped.State.Phase != PhaseFocus {
+		t.Errorf("Expected to stop during focus, got %q", stopped.State.Phase)
+	}
+	if stopped.State.Remaining != time.Second {
+		t.Errorf("Expected 1s remaining when stopped, got %v", stopped.State.Remaining)
+	}
+}
+
+func TestTimer_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, fake, events, done := startFakeTimerContext(ctx, Config{
+		FocusDuration: 3 * time.Second,
+		BreakDuration: 2 * time.Second,
+		RepeatCount:   1,
+	})
+
+	advance(fake, 1)
+	nextEvent(t, events, EventTick)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	stopped := nextEvent(t, events, EventStopped)
+	if stopped.State.Remaining != 2*time.Second {
+		t.Errorf("Expected 2s remaining when cancelled, got %v", stopped.State.Remaining)
+	}
+}
+
+func TestTimer_ContinueOnBreak(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration:   2 * time.Second,
+		BreakDuration:   1 * time.Second,
+		RepeatCount:     1,
+		ContinueOnBreak: true,
+	})
+
+	// Two full pomodoros, then one second into the third focus phase
+	advance(fake, 3+3+1)
+	for i := 0; i < 5; i++ {
+		nextEvent(t, events, EventPhaseStart)
+	}
+	nextEvent(t, events, EventTick)
+	timer.Stop()
+
+	if err := <-done; !errors.Is(err, ErrStopped) {
+		t.Fatalf("A continuing timer never completes on its own, got %v", err)
+	}
+	if state := timer.State(); state.Phase != PhaseFocus || state.Cycle != 1 {
+		t.Errorf("Expected to be back in focus of cycle 1, got %q cycle %d", state.Phase, state.Cycle)
+	}
+	for event := range events {
+		if event.Type == EventCompleted {
+			t.Error("A continuing timer should not emit a completed event")
+		}
+	}
+}
+
+func TestTimer_PauseResume(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 3 * time.Second,
+		BreakDuration: 2 * time.Second,
+		RepeatCount:   1,
+	})
+
+	fake.BlockUntil(1)
+	fake.Advance(400 * time.Millisecond)
+	timer.Pause()
+	if !timer.State().Paused {
+		t.Error("Timer should be paused after Pause")
+	}
+	// Pausing twice is a no-op
+	timer.Pause()
+
+	// Time passing while paused does not count
+	fake.Advance(time.Hour)
+	if remaining := timer.State().Remaining; remaining != 2600*time.Millisecond {
+		t.Errorf("Expected 2.6s remaining while paused, got %v", remaining)
+	}
+	if endsAt := timer.State().EndsAt; !endsAt.IsZero() {
+		t.Errorf("A paused phase should have no end time, got %v", endsAt)
+	}
+
+	timer.Resume()
+	if timer.State().Paused {
+		t.Error("Timer should not be paused after Resume")
+	}
+
+	resumed := fake.Now()
+	if endsAt := timer.State().EndsAt; !endsAt.Equal(resumed.Add(2600 * time.Millisecond)) {
+		t.Errorf("Expected the phase to end 2.6s after resuming, got %v", endsAt)
+	}
+
+	// The 600ms left in the interrupted second are kept exactly
+	fake.BlockUntil(1)
+	fake.Advance(599 * time.Millisecond)
+	if remaining := timer.State().Remaining; remaining != 2600*time.Millisecond {
+		t.Errorf("Expected no tick before the second was up, got %v remaining", remaining)
+	}
+	fake.Advance(time.Millisecond)
+	tick := nextEvent(t, events, EventTick)
+	if tick.State.Remaining != 2*time.Second {
+		t.Errorf("Expected 2s remaining after the tick, got %v", tick.State.Remaining)
+	}
+
+	timer.Stop()
+	if err := <-done; !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
+
+func TestTimer_PauseCarriesIntoNextPhase(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 1 * time.Second,
+		BreakDuration: 2 * time.Second,
+		RepeatCount:   1,
+	})
+
+	nextEvent(t, events, EventPhaseStart)
+	timer.Skip()
+	start := nextEvent(t, events, EventPhaseStart)
+	timer.Pause()
+	if start.State.Phase != PhaseBreak {
+		t.Fatalf("Expected the break to start after skipping focus, got %q", start.State.Phase)
+	}
+
+	fake.Advance(time.Minute)
+	if remaining := timer.State().Remaining; remaining != 2*time.Second {
+		t.Errorf("Expected the paused break to keep 2s, got %v", remaining)
+	}
+
+	timer.Resume()
+	advance(fake, 2)
+	if err := <-done; err != nil {
+		t.Errorf("Timer should complete once the resumed break ends, got %v", err)
+	}
+}
+
+func TestTimer_SkipAndExtend(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 25 * time.Minute,
+		BreakDuration: 5 * time.Minute,
+		RepeatCount:   1,
+	})
+
+	timer.Extend(5*time.Minute + 500*time.Millisecond)
+	extended := nextEvent(t, events, EventExtended)
+	if extended.State.Duration != 30*time.Minute || extended.State.Remaining != 30*time.Minute {
+		t.Errorf("Expected 30m duration and remaining after extend, got %v and %v",
+			extended.State.Duration, extended.State.Remaining)
+	}
+
+	timer.Skip()
+	skipped := nextEvent(t, events, EventSkipped)
+	if skipped.State.Phase != PhaseFocus {
+		t.Errorf("Expected the focus phase to be skipped, got %q", skipped.State.Phase)
+	}
+
+	// The break starts right after the skipped focus phase
+	start := nextEvent(t, events, EventPhaseStart)
+	if start.State.Phase != PhaseBreak || start.State.Remaining != 5*time.Minute {
+		t.Errorf("Expected a fresh 5m break after skip, got %q with %v", start.State.Phase, start.State.Remaining)
+	}
+
+	advance(fake, 60)
+	timer.Skip()
+	if err := <-done; err != nil {
+		t.Errorf("Skipping the last phase should complete the timer, got %v", err)
+	}
+	if phase := timer.State().Phase; phase != PhaseCompleted {
+		t.Errorf("Expected phase %q, got %q", PhaseCompleted, phase)
+	}
+}
+
+func TestTimer_SetTask(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 2 * time.Second,
+		BreakDuration: 1 * time.Second,
+		RepeatCount:   1,
+		Task:          "write RFC",
+	})
+
+	if task := nextEvent(t, events, EventPhaseStart).State.Task; task != "write RFC" {
+		t.Errorf("Expected the configured task, got %q", task)
+	}
+
+	timer.SetTask("review RFC")
+	if task := nextEvent(t, events, EventTaskChanged).State.Task; task != "review RFC" {
+		t.Errorf("Expected the new task, got %q", task)
+	}
+
+	// The task carries into the following phases
+	advance(fake, 2)
+	if start := nextEvent(t, events, EventPhaseStart); start.State.Phase != PhaseBreak || start.State.Task != "review RFC" {
+		t.Errorf("Expected the break to keep the task, got %q with %q", start.State.Phase, start.State.Task)
+	}
+
+	timer.Stop()
+	<-done
+}
+
+func TestTimer_BreakAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		every         int
+		longBreak     time.Duration
+		pomodoros     int
+		expectedPhase Phase
+	}{
+		{"ShortBreak", 4, 15 * time.Minute, 1, PhaseBreak},
+		{"LongBreak", 4, 15 * time.Minute, 4, PhaseLongBreak},
+		{"SecondLongBreak", 4, 15 * time.Minute, 8, PhaseLongBreak},
+		{"DisabledInterval", 0, 15 * time.Minute, 4, PhaseBreak},
+		{"DisabledDuration", 4, 0, 4, PhaseBreak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := NewTimer(Config{
+				FocusDuration:     25 * time.Minute,
+				BreakDuration:     5 * time.Minute,
+				LongBreakDuration: tt.longBreak,
+				LongBreakEvery:    tt.every,
+				RepeatCount:       8,
+			})
+
+			phase, duration := timer.breakAfter(tt.pomodoros)
+			if phase != tt.expectedPhase {
+				t.Errorf("Expected phase %q, got %q", tt.expectedPhase, phase)
+			}
+			expectedDuration := 5 * time.Minute
+			if tt.expectedPhase == PhaseLongBreak {
+				expectedDuration = tt.longBreak
+			}
+			if duration != expectedDuration {
+				t.Errorf("Expected duration %v, got %v", expectedDuration, duration)
+			}
+		})
+	}
+}
+
+func TestTimer_TicksFollowDeadline(t *testing.T) {
+	timer, fake, events, _ := startFakeTimer(Config{
+		FocusDuration: 10 * time.Second,
+		BreakDuration: 5 * time.Second,
+		RepeatCount:   1,
+	})
+	defer timer.Stop()
+
+	start := nextEvent(t, events, EventPhaseStart)
+	if !start.State.EndsAt.Equal(epoch.Add(10 * time.Second)) {
+		t.Errorf("Expected focus to end at %v, got %v", epoch.Add(10*time.Second), start.State.EndsAt)
+	}
+
+	// A tick delivered late reports the time actually left, not one second
+	// less than the previous tick.
+	fake.BlockUntil(1)
+	fake.Advance(3500 * time.Millisecond)
+	tick := nextEvent(t, events, EventTick)
+	if tick.State.Remaining != 6500*time.Millisecond {
+		t.Errorf("Expected 6.5s remaining after a late tick, got %v", tick.State.Remaining)
+	}
+
+	// The next tick lands back on a whole second
+	fake.BlockUntil(1)
+	fake.Advance(500 * time.Millisecond)
+	tick = nextEvent(t, events, EventTick)
+	if tick.State.Remaining != 6*time.Second {
+		t.Errorf("Expected 6s remaining, got %v", tick.State.Remaining)
+	}
+}
+
+func TestTimer_CatchesUpAfterSuspend(t *testing.T) {
+	timer, fake, events, done := startFakeTimer(Config{
+		FocusDuration: 10 * time.Second,
+		BreakDuration: 5 * time.Second,
+		RepeatCount:   2,
+	})
+
+	// Simulate a suspend: the wall clock jumps 22s while the pending tick
+	// only fires once on wake-up. Focus and break of cycle 1 were missed
+	// and 7s of the second focus phase have already gone by.
+	fake.BlockUntil(1)
+	fake.Advance(22 * time.Second)
+
+	var ends []Phase
+	for len(ends) < 2 {
+		event := nextEvent(t, events, EventPhaseEnd)
+		ends = append(ends, event.State.Phase)
+	}
+	if ends[0] != PhaseFocus || ends[1] != PhaseBreak {
+		t.Errorf("Expected the missed focus and break to end, got %v", ends)
+	}
+
+	start := nextEvent(t, events, EventPhaseStart)
+	if start.State.Phase != PhaseFocus || start.State.Cycle != 2 {
+		t.Fatalf("Expected focus of cycle 2, got %q cycle %d", start.State.Phase, start.State.Cycle)
+	}
+	if start.State.Remaining != 3*time.Second {
+		t.Errorf("Expected 3s left of the second focus, got %v", start.State.Remaining)
+	}
+
+	timer.Stop()
+	<-done
+}
@@ -0,0 +1,154 @@
+package sound
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/flac"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/wav"
+)
+
+// Limits on audio clips, so a phase-end cue cannot hold the speaker for
+// long.
+const (
+	maxLoops    = 10
+	maxClipPlay = time.Minute
+)
+
+// resampleQuality trades speed for fidelity when a clip is converted to
+// the speaker's rate; 4 is beep's usual choice.
+const resampleQuality = 4
+
+// wavFormat is 16-bit stereo at the speaker's rate, the format clips are
+// kept in and themes are rendered to.
+var wavFormat = beep.Format{SampleRate: sampleRate, NumChannels: 2, Precision: 2}
+
+// decoders open the audio files a clip can be made of, by extension.
+var decoders = map[string]func(io.Reader) (beep.StreamSeekCloser, beep.Format, error){
+	".wav":  wav.Decode,
+	".flac": flac.Decode,
+	".mp3":  decodeMP3,
+}
+
+// decodeMP3 adapts mp3.Decode, which wants a ReadCloser.
+func decodeMP3(r io.Reader) (beep.StreamSeekCloser, beep.Format, error) {
+	rc, ok := r.(io.ReadCloser)
+	if !ok {
+		rc = io.NopCloser(r)
+	}
+	return mp3.Decode(rc)
+}
+
+// isAudioFile reports whether path names an audio file by its extension.
+func isAudioFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return decoders[ext] != nil
+}
+
+// audioFormats lists the extensions decoders can play.
+func audioFormats() string {
+	exts := make([]string, 0, len(decoders))
+	for ext := range decoders {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return strings.Join(exts, ", ")
+}
+
+// LoadClip reads the audio file at path into a theme named after the file,
+// playing it loops times over. The clip is decoded and resampled to the
+// speaker's rate up front, so a broken file shows up before the session
+// starts rather than when a phase ends.
+func LoadClip(path string, loops int) (Theme, error) {
+	name := themeName(filepath.Base(path))
+	if loops < 1 || loops > maxLoops {
+		return Theme{}, fmt.Errorf("theme %s: loops must be between 1 and %d, got %d", name, maxLoops, loops)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	decode := decoders[ext]
+	if decode == nil {
+		return Theme{}, fmt.Errorf("sound: %s: cannot play %s files, only %s", path, ext, audioFormats())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("sound: %w", err)
+	}
+	defer file.Close()
+	// A clip longer than the limit is cut off one sample past it, so a
+	// whole song is never read into memory only to be refused.
+	limit := beep.SampleRate(sampleRate).N(maxClipPlay) + 1
+	clip, err := decodeClip(decode, file, limit)
+	if err != nil {
+		return Theme{}, fmt.Errorf("sound: %s: %w", path, err)
+	}
+	if clip.Len() == 0 {
+		return Theme{}, fmt.Errorf("sound: %s: no audio", path)
+	}
+	if clip.Len() >= limit {
+		return Theme{}, fmt.Errorf("theme %s: plays for longer than the %s limit", name, maxClipPlay)
+	}
+
+	theme := Theme{Name: name, clip: clip, loops: loops}
+	if d := theme.Duration(); d > maxClipPlay {
+		return Theme{}, fmt.Errorf("theme %s: plays for %s, longer than the %s limit", name, d.Round(time.Second), maxClipPlay)
+	}
+	return theme, nil
+}
+
+// decodeClip decodes at most limit samples of r into a buffer at the
+// speaker's rate. The FLAC decoder panics on sample sizes it does not
+// handle, which is reported as an error like any other unreadable file.
+func decodeClip(decode func(io.Reader) (beep.StreamSeekCloser, beep.Format, error), r io.Reader, limit int) (clip *beep.Buffer, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			clip, err = nil, fmt.Errorf("%v", p)
+		}
+	}()
+
+	stream, format, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	clip = beep.NewBuffer(wavFormat)
+	clip.Append(beep.Take(limit, beep.Resample(resampleQuality, format.SampleRate, sampleRate, stream)))
+	// Some decoders report the end of the stream as io.EOF.
+	if err := stream.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return clip, nil
+}
+
+// LoadFile reads a theme file or an audio file, telling them apart by
+// extension. A theme file's clip is found relative to the theme file.
+func LoadFile(path string) (Theme, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return LoadClip(path, 1)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("sound: %w", err)
+	}
+	return parseTheme(themeName(filepath.Base(path)), data, filepath.Dir(path))
+}
+
+// Render writes the theme to w as a WAV file, exactly as Play would send
+// it to the speaker.
+func (t Theme) Render(w io.WriteSeeker) error {
+	if err := wav.Encode(w, t.streamer(), wavFormat); err != nil {
+		return fmt.Errorf("sound: rendering %s: %w", t.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package sound
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// writeWAV writes a mono clip of length d at rate to dir/name.
+func writeWAV(t *testing.T, dir, name string, rate beep.SampleRate, d time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	format := beep.Format{SampleRate: rate, NumChannels: 1, Precision: 2}
+	if err := wav.Encode(file, beep.Take(rate.N(d), synthesize(note{freqs: []float64{440}, duration: d}, defaultVoice)), format); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadClip(t *testing.T) {
+	recorder := record(t)
+	path := writeWAV(t, t.TempDir(), "horn.wav", 22050, 200*time.Millisecond)
+
+	theme, err := LoadClip(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Name != "horn" {
+		t.Errorf("Name = %q, want horn", theme.Name)
+	}
+	// Resampled from 22.05 kHz, the clip keeps its length at 44.1 kHz.
+	if got := theme.clip.Len(); got < 8800 || got > 8840 {
+		t.Errorf("clip has %d samples, want about 8820", got)
+	}
+	if d := theme.Duration(); d < 590*time.Millisecond || d > 610*time.Millisecond {
+		t.Errorf("Duration() = %s, want about 600ms for three loops", d)
+	}
+	if err := theme.Play(); err != nil || len(recorder.Played()) != 1 {
+		t.Errorf("Play() = %v, played %v", err, playedNames(recorder))
+	}
+	if notes := theme.Notes(); len(notes) != 0 {
+		t.Errorf("a clip has no notes, got %v", notes)
+	}
+}
+
+func TestLoadClip_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeWAV(t, dir, "horn.wav", 44100, 100*time.Millisecond)
+	long := writeWAV(t, dir, "long.wav", 8000, 7*time.Second)
+	song := writeWAV(t, dir, "song.wav", 8000, 61*time.Second)
+	broken := filepath.Join(dir, "broken.wav")
+	os.WriteFile(broken, []byte("RIFF, but not really"), 0o644)
+	ogg := filepath.Join(dir, "horn.ogg")
+	os.WriteFile(ogg, []byte("OggS"), 0o644)
+	brokenFLAC := filepath.Join(dir, "broken.flac")
+	os.WriteFile(brokenFLAC, []byte("fLaC, but not really"), 0o644)
+	brokenMP3 := filepath.Join(dir, "broken.mp3")
+	os.WriteFile(brokenMP3, []byte("ID3, but not really"), 0o644)
+
+	tests := []struct {
+		path  string
+		loops int
+		want  string
+	}{
+		{filepath.Join(dir, "missing.wav"), 1, "missing.wav"},
+		{broken, 1, broken},
+		{ogg, 1, "cannot play .ogg files, only .flac, .mp3, .wav"},
+		{brokenFLAC, 1, brokenFLAC},
+		{brokenMP3, 1, brokenMP3},
+		{path, 0, "loops must be between 1 and 10"},
+		{path, 11, "loops must be between 1 and 10"},
+		{long, 9, "longer than the 1m0s limit"},
+		{song, 1, "longer than the 1m0s limit"},
+	}
+	for _, tt := range tests {
+		_, err := LoadClip(tt.path, tt.loops)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("LoadClip(%s, %d) error = %v, want %q", filepath.Base(tt.path), tt.loops, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeClip_Limit(t *testing.T) {
+	file, err := os.Open(writeWAV(t, t.TempDir(), "song.wav", 44100, time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	clip, err := decodeClip(wav.Decode, file, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clip.Len() != 100 {
+		t.Errorf("decoded %d samples, want the clip cut off at 100", clip.Len())
+	}
+}
+
+func TestLoadClip_Formats(t *testing.T) {
+	tests := []struct {
+		path     string
+		duration time.Duration
+	}{
+		// A 440 Hz tone, 22.05 kHz mono, resampled to the speaker's rate.
+		{"testdata/tone.flac", 200 * time.Millisecond},
+		// Eight silent 1152-sample frames at 44.1 kHz.
+		{"testdata/silence.mp3", 209 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		theme, err := LoadFile(tt.path)
+		if err != nil {
+			t.Errorf("LoadFile(%s): %v", tt.path, err)
+			continue
+		}
+		if d := theme.Duration(); d < tt.duration-5*time.Millisecond || d > tt.duration+5*time.Millisecond {
+			t.Errorf("%s plays for %s, want about %s", tt.path, d, tt.duration)
+		}
+	}
+
+	tone, err := LoadClip("testdata/tone.flac", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loudest := 0.0
+	for _, s := range samples(tone.streamer()) {
+		loudest = max(loudest, math.Abs(s[0]))
+	}
+	if loudest < 0.2 || loudest > 0.3 {
+		t.Errorf("FLAC tone peaks at %.2f, want about 0.24", loudest)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	writeWAV(t, dir, "horn.wav", 44100, 100*time.Millisecond)
+	theme := filepath.Join(dir, "gondor.yaml")
+	os.WriteFile(theme, []byte("file: horn.wav\nloops: 2\n"), 0o644)
+
+	got, err := LoadFile(theme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "gondor" || got.loops != 2 || got.clip == nil {
+		t.Errorf("LoadFile = %+v, want the horn clip twice", got)
+	}
+
+	both := filepath.Join(dir, "both.yaml")
+	os.WriteFile(both, []byte("file: horn.wav\nnotes: [G4 100ms]\n"), 0o644)
+	if _, err := LoadFile(both); err == nil || !strings.Contains(err.Error(), "both notes and a file") {
+		t.Errorf("LoadFile error = %v, want notes and file refused together", err)
+	}
+}
+
+func TestLoadThemes_Audio(t *testing.T) {
+	dir := t.TempDir()
+	writeWAV(t, dir, "horn.wav", 44100, 100*time.Millisecond)
+	os.WriteFile(filepath.Join(dir, "bell.flac"), []byte("fLaC"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not audio"), 0o644)
+
+	themes, err := LoadThemes(dir)
+	if err == nil || !strings.Contains(err.Error(), "bell.flac") || strings.Contains(err.Error(), "notes.txt") {
+		t.Errorf("LoadThemes error = %v, want the broken FLAC file reported and other files left alone", err)
+	}
+	if horn, err := themes.Get("horn"); err != nil || horn.clip == nil {
+		t.Errorf("Get(horn) = %+v, %v, want the clip", horn, err)
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// decodeWAV reads back a rendered file's samples.
+func decodeWAV(t *testing.T, data []byte) [][2]float64 {
+	t.Helper()
+	stream, format, err := wav.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != wavFormat {
+		t.Errorf("format = %+v, want %+v", format, wavFormat)
+	}
+	return samples(stream)
+}
+
+// render renders theme to a file and returns the file's contents.
+func render(t *testing.T, theme Theme) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), theme.Name+".wav")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := theme.Render(file); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRender_Golden(t *testing.T) {
+	theme, err := LoadFile("testdata/golden.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := render(t, theme)
+	if *update {
+		if err := os.WriteFile("testdata/golden.wav", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile("testdata/golden.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Samples are compared to within a step of 16-bit audio, so floating
+	// point differences between machines do not matter.
+	gotSamples, wantSamples := decodeWAV(t, got), decodeWAV(t, want)
+	if len(gotSamples) != len(wantSamples) {
+		t.Fatalf("rendered %d samples, want %d", len(gotSamples), len(wantSamples))
+	}
+	for i := range gotSamples {
+		for c := range gotSamples[i] {
+			if math.Abs(gotSamples[i][c]-wantSamples[i][c]) > 2.0/(1<<15) {
+				t.Fatalf("sample %d = %v, want %v", i, gotSamples[i], wantSamples[i])
+			}
+		}
+	}
+}
+
+func TestRender_Builtins(t *testing.T) {
+	for _, name := range Builtins().Names() {
+		theme := builtin(name)
+		got := len(decodeWAV(t, render(t, theme)))
+		if want := beep.SampleRate(sampleRate).N(theme.Duration()); got < want-len(theme.notes)*2 || got > want {
+			t.Errorf("%s renders %d samples, want about %d", name, got, want)
+		}
+	}
+}
+
+func TestRender_Clip(t *testing.T) {
+	dir := t.TempDir()
+	clip, err := LoadClip(writeWAV(t, dir, "horn.wav", 44100, 100*time.Millisecond), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(decodeWAV(t, render(t, clip))); got != 2*clip.clip.Len() {
+		t.Errorf("rendered %d samples, want the clip twice, %d", got, 2*clip.clip.Len())
+	}
+}
@@ -0,0 +1,144 @@
+package sound
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+)
+
+// Output is where themes play.
+type Output interface {
+	// Play plays theme and returns once it is over.
+	Play(theme Theme) error
+}
+
+var (
+	outputMu sync.RWMutex
+	output   Output = Speaker{}
+)
+
+// SetOutput makes themes play on o from now on, and returns the output
+// they played on until now.
+func SetOutput(o Output) Output {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	previous := output
+	output = o
+	return previous
+}
+
+func currentOutput() Output {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	return output
+}
+
+// ParseOutput picks an output by name: speaker, none, or the path of a
+// .wav file to record into.
+func ParseOutput(name string) (Output, error) {
+	switch {
+	case name == "" || name == "speaker":
+		return Speaker{}, nil
+	case name == "none":
+		return Null{}, nil
+	case strings.EqualFold(filepath.Ext(name), ".wav"):
+		return NewWAVFile(name), nil
+	}
+	return nil, fmt.Errorf("unknown sound output %q, expected speaker, none or a .wav file", name)
+}
+
+// Speaker plays themes on the default audio device, opened the first time
+// a theme plays.
+type Speaker struct{}
+
+var (
+	speakerOnce sync.Once
+	speakerErr  error
+)
+
+func (Speaker) Play(theme Theme) error {
+	speakerOnce.Do(func() {
+		speakerErr = speaker.Init(beep.SampleRate(sampleRate), sampleRate/10)
+	})
+	if speakerErr != nil {
+		return fmt.Errorf("sound: %w", speakerErr)
+	}
+
+	done := make(chan bool)
+	speaker.Play(beep.Seq(theme.streamer(), beep.Callback(func() { done <- true })))
+	<-done
+	return nil
+}
+
+// Null plays nothing, at once.
+type Null struct{}
+
+func (Null) Play(Theme) error {
+	return nil
+}
+
+// Recorder keeps the themes played on it instead of playing them.
+type Recorder struct {
+	mu     sync.Mutex
+	played []Theme
+}
+
+func (r *Recorder) Play(theme Theme) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.played = append(r.played, theme)
+	return nil
+}
+
+// Played returns the themes played so far, in order.
+func (r *Recorder) Played() []Theme {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Theme(nil), r.played...)
+}
+
+// WAVFile records the themes played on it one after the other, and writes
+// them to a WAV file on Close.
+type WAVFile struct {
+	path string
+
+	mu     sync.Mutex
+	buffer *beep.Buffer
+}
+
+// NewWAVFile returns an output recording into the file at path.
+func NewWAVFile(path string) *WAVFile {
+	return &WAVFile{path: path, buffer: beep.NewBuffer(wavFormat)}
+}
+
+func (w *WAVFile) Play(theme Theme) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buffer.Append(theme.streamer())
+	return nil
+}
+
+// Close writes what was played to the file.
+func (w *WAVFile) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	file, err := os.Create(w.path)
+	if err != nil {
+		return fmt.Errorf("sound: %w", err)
+	}
+	recording := Theme{Name: filepath.Base(w.path), clip: w.buffer, loops: 1}
+	if err := recording.Render(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("sound: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package sound
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := map[string]string{
+		"":              "sound.Speaker",
+		"speaker":       "sound.Speaker",
+		"none":          "sound.Null",
+		"out/cues.WAV":  "*sound.WAVFile",
+		"session.wav":   "*sound.WAVFile",
+		"headphones":    "",
+		"recording.mp3": "",
+	}
+	for name, want := range tests {
+		output, err := ParseOutput(name)
+		if want == "" {
+			if err == nil || !strings.Contains(err.Error(), "unknown sound output") {
+				t.Errorf("ParseOutput(%q) error = %v, want an unknown output", name, err)
+			}
+			continue
+		}
+		if got := typeName(output); err != nil || got != want {
+			t.Errorf("ParseOutput(%q) = %s, %v, want %s", name, got, err, want)
+		}
+	}
+}
+
+func typeName(output Output) string {
+	switch output.(type) {
+	case Speaker:
+		return "sound.Speaker"
+	case Null:
+		return "sound.Null"
+	case *WAVFile:
+		return "*sound.WAVFile"
+	}
+	return ""
+}
+
+func TestSetOutput(t *testing.T) {
+	first := &Recorder{}
+	previous := SetOutput(first)
+	defer SetOutput(previous)
+
+	builtin("elves").Play()
+	if got := SetOutput(Null{}); got != first {
+		t.Errorf("SetOutput returned %v, want the recorder", got)
+	}
+	builtin("hobbits").Play()
+
+	played := first.Played()
+	if len(played) != 1 || played[0].Name != "elves" {
+		t.Fatalf("recorder played %v, want only elves", playedNames(first))
+	}
+	if got, want := strings.Join(played[0].Notes(), ", "), "E5+B4 250ms, D5 250ms, C5+E4+G4 375ms, D5 250ms, E5+C5+G4 500ms"; got != want {
+		t.Errorf("elves played %s, want %s", got, want)
+	}
+}
+
+func TestWAVFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.wav")
+	output := NewWAVFile(path)
+	previous := SetOutput(output)
+	defer SetOutput(previous)
+
+	soft, elves := builtin("soft-focus"), builtin("elves")
+	soft.Play()
+	elves.Play()
+	if err := output.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	recording, err := LoadClip(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := soft.Duration() + elves.Duration()
+	if got := recording.Duration(); got < want-time.Millisecond || got > want {
+		t.Errorf("recording lasts %s, want the two themes, %s", got, want)
+	}
+
+	if err := NewWAVFile(filepath.Join(t.TempDir(), "missing", "x.wav")).Close(); err == nil {
+		t.Error("Close into a missing directory should fail")
+	}
+}
@@ -1,57 +1,27 @@
 package sound
 
 import (
-	"math"
-	"sync"
 	"time"
 
 	"github.com/faiface/beep"
-	"github.com/faiface/beep/speaker"
 )
 
 const sampleRate = 44100
 
-var Mute bool = false
-
-var speakerOnce sync.Once
-
+// note is a tone, a chord when it has more than one frequency, or silence
+// when it has none.
 type note struct {
-	freq     float64
+	name     string
+	freqs    []float64
 	duration time.Duration
 }
 
-func generateTone(frequency float64, duration time.Duration) beep.Streamer {
-	streamer := beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
-		for i := range samples {
-			t := float64(i) / float64(sampleRate)
-			v := 0.2 * math.Sin(2*math.Pi*frequency*t)
-			samples[i][0] = v
-			samples[i][1] = v
-		}
-		return len(samples), true
-	})
-	return beep.Take(beep.SampleRate(sampleRate).N(duration), streamer)
-}
-
-func playSequence(notes []note) {
-	if Mute {
-		return
-	}
-
-	speakerOnce.Do(func() {
-		speaker.Init(beep.SampleRate(sampleRate), sampleRate/10)
-	})
-
+// sequence plays notes one after the other in v, with gap after each.
+func sequence(notes []note, gap time.Duration, v voice) beep.Streamer {
 	var streamers []beep.Streamer
 	for _, n := range notes {
-		tone := generateTone(n.freq, n.duration)
-		silence := beep.Silence(beep.SampleRate(sampleRate).N(30 * time.Millisecond))
-		streamers = append(streamers, tone, silence)
+		silence := beep.Silence(beep.SampleRate(sampleRate).N(gap))
+		streamers = append(streamers, synthesize(n, v), silence)
 	}
-
-	done := make(chan bool)
-	speaker.Play(beep.Seq(
-		append(streamers, beep.Callback(func() { done <- true }))...,
-	))
-	<-done
+	return beep.Seq(streamers...)
 }
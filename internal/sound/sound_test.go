@@ -2,17 +2,40 @@ package sound
 
 import "testing"
 
-func TestThemeHobbits(t *testing.T) {
-	Mute = true
-	ThemeHobbits()
+// record makes themes play on a recorder for the rest of the test.
+func record(t testing.TB) *Recorder {
+	recorder := &Recorder{}
+	previous := SetOutput(recorder)
+	t.Cleanup(func() { SetOutput(previous) })
+	return recorder
 }
 
-func TestThemeElves(t *testing.T) {
-	Mute = true
-	ThemeElves()
+// playedNames lists the names of the themes played on r.
+func playedNames(r *Recorder) []string {
+	var names []string
+	for _, theme := range r.Played() {
+		names = append(names, theme.Name)
+	}
+	return names
 }
 
-func TestThemeAragorn(t *testing.T) {
-	Mute = true
+func TestNamedThemes(t *testing.T) {
+	recorder := record(t)
+	ThemeHobbits()
+	ThemeElves()
 	ThemeAragorn()
+	ThemeMinasTirith()
+	ThemeMountDoom()
+
+	want := []string{"hobbits", "elves", "aragorn", "minas-tirith", "mount-doom"}
+	got := playedNames(recorder)
+	if len(got) != len(want) {
+		t.Fatalf("played %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("played %v, want %v", got, want)
+			break
+		}
+	}
 }
@@ -0,0 +1,122 @@
+package sound
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// volume is the peak level of a note, shared among a chord's voices.
+const volume = 0.2
+
+// A waveform gives the level of a wave, between -1 and 1, at phase p of
+// its period, 0 <= p < 1.
+type waveform func(p float64) float64
+
+// waveforms are the waves a theme can be played with. Square and saw are
+// halved so they sound about as loud as the other two.
+var waveforms = map[string]waveform{
+	"sine": func(p float64) float64 { return math.Sin(2 * math.Pi * p) },
+	"square": func(p float64) float64 {
+		if p < 0.5 {
+			return 0.5
+		}
+		return -0.5
+	},
+	"triangle": func(p float64) float64 { return 1 - 4*math.Abs(p-0.5) },
+	"saw":      func(p float64) float64 { return p - 0.5 },
+}
+
+// waveNames lists the waveforms in alphabetical order.
+func waveNames() string {
+	names := make([]string, 0, len(waveforms))
+	for name := range waveforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// envelope shapes a note's level: it rises to full over attack, falls to
+// sustain over decay and holds there, then fades from whatever level it
+// reached to silence over release, all within the note's duration.
+type envelope struct {
+	attack, decay time.Duration
+	sustain       float64
+	release       time.Duration
+}
+
+// defaultEnvelope only softens the edges of a note, so it does not click.
+var defaultEnvelope = envelope{attack: 5 * time.Millisecond, decay: 40 * time.Millisecond, sustain: 0.8, release: 40 * time.Millisecond}
+
+// level is the envelope's level at sample i of a note n samples long. A
+// note too short for the whole envelope gets a proportionally shorter one.
+func (e envelope) level(i, n int) float64 {
+	rate := beep.SampleRate(sampleRate)
+	attack, decay, release := float64(rate.N(e.attack)), float64(rate.N(e.decay)), float64(rate.N(e.release))
+	if total := attack + decay + release; total > float64(n) {
+		scale := float64(n) / total
+		attack, decay, release = attack*scale, decay*scale, release*scale
+	}
+
+	x := float64(i)
+	level := e.sustain
+	switch {
+	case x < attack:
+		level = x / attack
+	case x < attack+decay:
+		level = 1 - (1-e.sustain)*(x-attack)/decay
+	}
+	if fromEnd := float64(n - i); fromEnd < release {
+		level *= fromEnd / release
+	}
+	return level
+}
+
+// voice is how a theme's notes sound.
+type voice struct {
+	wave     waveform
+	envelope envelope
+}
+
+var defaultVoice = voice{wave: waveforms["sine"], envelope: defaultEnvelope}
+
+// synthesize plays n in v, sounding every frequency of a chord at once.
+func synthesize(n note, v voice) beep.Streamer {
+	total := beep.SampleRate(sampleRate).N(n.duration)
+	if len(n.freqs) == 0 {
+		return beep.Silence(total)
+	}
+	i := 0
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if i >= total {
+			return 0, false
+		}
+		k := 0
+		for ; k < len(samples) && i < total; k++ {
+			t := float64(i) / sampleRate
+			var level float64
+			for _, freq := range n.freqs {
+				_, p := math.Modf(freq * t)
+				level += v.wave(p)
+			}
+			level *= volume / float64(len(n.freqs)) * v.envelope.level(i, total)
+			samples[k] = [2]float64{level, level}
+			i++
+		}
+		return k, true
+	})
+}
+
+// parseWave looks up a waveform by name.
+func parseWave(name string) (waveform, error) {
+	wave, ok := waveforms[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown wave %q, expected one of %s", name, waveNames())
+	}
+	return wave, nil
+}
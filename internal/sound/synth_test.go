@@ -0,0 +1,83 @@
+package sound
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// samples runs s to the end.
+func samples(s beep.Streamer) [][2]float64 {
+	var out [][2]float64
+	buf := make([][2]float64, 512)
+	for {
+		n, ok := s.Stream(buf)
+		out = append(out, buf[:n]...)
+		if !ok {
+			return out
+		}
+	}
+}
+
+func TestWaveforms(t *testing.T) {
+	tests := []struct {
+		wave string
+		at   []float64
+		want []float64
+	}{
+		{"sine", []float64{0, 0.25, 0.75}, []float64{0, 1, -1}},
+		{"square", []float64{0, 0.49, 0.5}, []float64{0.5, 0.5, -0.5}},
+		{"triangle", []float64{0, 0.25, 0.5}, []float64{-1, 0, 1}},
+		{"saw", []float64{0, 0.5, 0.75}, []float64{-0.5, 0, 0.25}},
+	}
+	for _, tt := range tests {
+		wave, err := parseWave(tt.wave)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, p := range tt.at {
+			if got := wave(p); math.Abs(got-tt.want[i]) > 1e-9 {
+				t.Errorf("%s(%v) = %v, want %v", tt.wave, p, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	// 441, 441 and 882 samples over a 4410 sample note.
+	e := envelope{attack: 10 * time.Millisecond, decay: 10 * time.Millisecond, sustain: 0.5, release: 20 * time.Millisecond}
+	tests := map[int]float64{0: 0, 147: 1.0 / 3, 441: 1, 661: 0.75, 2000: 0.5, 3969: 0.25, 4409: 0}
+	for i, want := range tests {
+		if got := e.level(i, 4410); math.Abs(got-want) > 0.001 {
+			t.Errorf("level(%d) = %v, want %v", i, got, want)
+		}
+	}
+	// A note shorter than the whole envelope squeezes every stage alike.
+	if got := e.level(110, 441); math.Abs(got-1) > 0.01 {
+		t.Errorf("short note level(110) = %v, want about 1 at the end of its attack", got)
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	n := note{freqs: []float64{440, 660}, duration: 100 * time.Millisecond}
+	out := samples(synthesize(n, defaultVoice))
+	if len(out) != 4410 {
+		t.Fatalf("synthesize made %d samples, want 4410", len(out))
+	}
+	// The envelope starts and ends the note in silence, so it cannot click.
+	if out[0][0] != 0 || math.Abs(out[len(out)-1][0]) > 0.001 {
+		t.Errorf("edges = %v, %v, want silence", out[0], out[len(out)-1])
+	}
+	for i, s := range out {
+		if math.Abs(s[0]) > volume || s[0] != s[1] {
+			t.Fatalf("sample %d = %v, want both channels within the volume", i, s)
+		}
+	}
+
+	rest := samples(synthesize(note{duration: 10 * time.Millisecond}, defaultVoice))
+	if len(rest) != 441 || rest[200] != [2]float64{} {
+		t.Errorf("rest = %d samples, want 441 silent ones", len(rest))
+	}
+}
@@ -0,0 +1,416 @@
+package sound
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+	"github.com/faiface/beep"
+	"gopkg.in/yaml.v3"
+)
+
+// A theme file lists notes as "<note> <duration>", where a note is a
+// name such as G4, C#5 or Bb3, a chord such as C4+E4+G4, or rest for
+// silence. With a tempo, durations can be counted in beats. JSON works
+// too.
+//
+//	# ~/.config/aragomodoro/themes/shire.yaml
+//	gap: 30ms       # silence after every note, 30ms when left out
+//	wave: triangle  # sine (the default), square, triangle or saw
+//	tempo: 120      # beats per minute
+//	envelope: {attack: 10ms, decay: 80ms, sustain: 0.6, release: 100ms}
+//	notes:
+//	  - G4 1/2
+//	  - rest 100ms
+//	  - C4+E4+G4 1.5
+//
+// A theme can play an audio file instead, found relative to the theme
+// file, any number of times up to maxLoops:
+//
+//	file: horn.wav
+//	loops: 2    # once when left out
+type themeFile struct {
+	Gap      string        `yaml:"gap"`
+	Wave     string        `yaml:"wave"`
+	Tempo    float64       `yaml:"tempo"`
+	Envelope *envelopeFile `yaml:"envelope"`
+	Notes    []string      `yaml:"notes"`
+	File     string        `yaml:"file"`
+	Loops    int           `yaml:"loops"`
+}
+
+// envelopeFile is a theme's envelope; what it leaves out keeps the
+// default.
+type envelopeFile struct {
+	Attack  string   `yaml:"attack"`
+	Decay   string   `yaml:"decay"`
+	Sustain *float64 `yaml:"sustain"`
+	Release string   `yaml:"release"`
+}
+
+// defaultGap keeps repeated notes apart.
+const defaultGap = 30 * time.Millisecond
+
+// Theme is a melody, or an audio clip, played at the end of a phase.
+type Theme struct {
+	Name  string
+	notes []note
+	gap   time.Duration
+	voice voice
+	clip  *beep.Buffer
+	loops int
+}
+
+// Play plays the theme on the output set with SetOutput, the speaker by
+// default, and returns once it is over.
+func (t Theme) Play() error {
+	if t.clip == nil && len(t.notes) == 0 {
+		return nil
+	}
+	return currentOutput().Play(t)
+}
+
+// Notes lists the theme's notes as a theme file would, such as "G4 300ms",
+// "C4+E4+G4 1s" or "rest 100ms". A clip has none.
+func (t Theme) Notes() []string {
+	notes := make([]string, len(t.notes))
+	for i, n := range t.notes {
+		notes[i] = n.name + " " + n.duration.String()
+	}
+	return notes
+}
+
+// streamer is the theme's audio at sampleRate, for the speaker or a file.
+func (t Theme) streamer() beep.Streamer {
+	if t.clip != nil {
+		return beep.Loop(t.loops, t.clip.Streamer(0, t.clip.Len()))
+	}
+	return sequence(t.notes, t.gap, t.voice)
+}
+
+// Duration is how long the theme plays for.
+func (t Theme) Duration() time.Duration {
+	if t.clip != nil {
+		return time.Duration(t.loops) * beep.SampleRate(sampleRate).D(t.clip.Len())
+	}
+	var d time.Duration
+	for _, n := range t.notes {
+		d += n.duration + t.gap
+	}
+	return d
+}
+
+// ParseTheme reads a theme file's contents. An audio file it names is
+// found relative to the current directory.
+func ParseTheme(name string, data []byte) (Theme, error) {
+	return parseTheme(name, data, ".")
+}
+
+// parseTheme reads a theme file kept in dir.
+func parseTheme(name string, data []byte, dir string) (Theme, error) {
+	var file themeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+	}
+	if file.File != "" {
+		if len(file.Notes) > 0 {
+			return Theme{}, fmt.Errorf("theme %s: has both notes and a file", name)
+		}
+		loops := file.Loops
+		if loops == 0 {
+			loops = 1
+		}
+		path := file.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		theme, err := LoadClip(path, loops)
+		theme.Name = name
+		return theme, err
+	}
+	if len(file.Notes) == 0 {
+		return Theme{}, fmt.Errorf("theme %s: no notes", name)
+	}
+
+	if file.Tempo < 0 {
+		return Theme{}, fmt.Errorf("theme %s: bad tempo %v", name, file.Tempo)
+	}
+
+	theme := Theme{Name: name, gap: defaultGap, voice: defaultVoice}
+	if file.Gap != "" {
+		gap, err := parseDuration(file.Gap, file.Tempo)
+		if err != nil || gap < 0 {
+			return Theme{}, fmt.Errorf("theme %s: bad gap %q", name, file.Gap)
+		}
+		theme.gap = gap
+	}
+	if file.Wave != "" {
+		wave, err := parseWave(file.Wave)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+		}
+		theme.voice.wave = wave
+	}
+	if file.Envelope != nil {
+		env, err := parseEnvelope(*file.Envelope)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %s: envelope: %w", name, err)
+		}
+		theme.voice.envelope = env
+	}
+	for i, entry := range file.Notes {
+		n, err := parseNote(entry, file.Tempo)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %s: note %d: %w", name, i+1, err)
+		}
+		theme.notes = append(theme.notes, n)
+	}
+	return theme, nil
+}
+
+var noteName = regexp.MustCompile(`^([A-Ga-g])([#b]?)(-?[0-9])$`)
+
+// semitones are the steps from C up to each natural note.
+var semitones = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+func parseNote(entry string, tempo float64) (note, error) {
+	fields := strings.Fields(entry)
+	if len(fields) != 2 {
+		return note{}, fmt.Errorf("%q is not \"<note> <duration>\"", entry)
+	}
+	duration, err := parseDuration(fields[1], tempo)
+	if err != nil {
+		return note{}, err
+	}
+	if duration <= 0 {
+		return note{}, fmt.Errorf("bad duration %q", fields[1])
+	}
+	if strings.EqualFold(fields[0], "rest") {
+		return note{name: "rest", duration: duration}, nil
+	}
+	var freqs []float64
+	for _, name := range strings.Split(fields[0], "+") {
+		freq, err := noteFrequency(name)
+		if err != nil {
+			return note{}, err
+		}
+		freqs = append(freqs, freq)
+	}
+	return note{name: fields[0], freqs: freqs, duration: duration}, nil
+}
+
+// parseDuration reads a duration such as 300ms, or a number of beats at
+// tempo such as 2, 1.5 or 1/2.
+func parseDuration(s string, tempo float64) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	beats, err := parseBeats(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad duration %q", s)
+	}
+	if tempo == 0 {
+		return 0, fmt.Errorf("duration %q is in beats, but the theme has no tempo", s)
+	}
+	return time.Duration(beats * float64(time.Minute) / tempo), nil
+}
+
+// parseBeats reads a number of beats, written as a decimal or a fraction.
+func parseBeats(s string) (float64, error) {
+	num, den, fraction := strings.Cut(s, "/")
+	beats, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, err
+	}
+	if fraction {
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil || d == 0 {
+			return 0, fmt.Errorf("bad fraction %q", s)
+		}
+		beats /= d
+	}
+	return beats, nil
+}
+
+// parseEnvelope fills in the default envelope with what f sets.
+func parseEnvelope(f envelopeFile) (envelope, error) {
+	env := defaultEnvelope
+	for _, field := range []struct {
+		name  string
+		value string
+		to    *time.Duration
+	}{
+		{"attack", f.Attack, &env.attack},
+		{"decay", f.Decay, &env.decay},
+		{"release", f.Release, &env.release},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil || d < 0 {
+			return envelope{}, fmt.Errorf("bad %s %q", field.name, field.value)
+		}
+		*field.to = d
+	}
+	if f.Sustain != nil {
+		if *f.Sustain < 0 || *f.Sustain > 1 {
+			return envelope{}, fmt.Errorf("sustain %v is not between 0 and 1", *f.Sustain)
+		}
+		env.sustain = *f.Sustain
+	}
+	return env, nil
+}
+
+// noteFrequency tunes name in equal temperament with A4 at 440 Hz.
+func noteFrequency(name string) (float64, error) {
+	m := noteName.FindStringSubmatch(name)
+	if m == nil {
+		return 0, fmt.Errorf("bad note %q", name)
+	}
+	key := semitones[strings.ToUpper(m[1])[0]]
+	switch m[2] {
+	case "#":
+		key++
+	case "b":
+		key--
+	}
+	octave := int(m[3][len(m[3])-1] - '0')
+	if strings.HasPrefix(m[3], "-") {
+		octave = -octave
+	}
+	midi := (octave+1)*12 + key
+	return 440 * math.Pow(2, float64(midi-69)/12), nil
+}
+
+// Registry holds themes by name.
+type Registry struct {
+	themes map[string]Theme
+}
+
+// Get returns the theme called name.
+func (r *Registry) Get(name string) (Theme, error) {
+	theme, ok := r.themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown sound theme %q, expected one of %s", name, strings.Join(r.Names(), ", "))
+	}
+	return theme, nil
+}
+
+// Names lists the themes in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.themes))
+	for name := range r.themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//go:embed themes/*.yaml
+var themeFiles embed.FS
+
+// Builtins are the themes shipped with aragomodoro.
+var Builtins = sync.OnceValue(func() *Registry {
+	r := &Registry{themes: make(map[string]Theme)}
+	entries, _ := themeFiles.ReadDir("themes")
+	for _, entry := range entries {
+		data, _ := themeFiles.ReadFile(path.Join("themes", entry.Name()))
+		theme, err := ParseTheme(themeName(entry.Name()), data)
+		if err != nil {
+			panic(err)
+		}
+		r.themes[theme.Name] = theme
+	}
+	return r
+})
+
+// builtin returns a theme shipped with aragomodoro.
+func builtin(name string) Theme {
+	theme, err := Builtins().Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return theme
+}
+
+// DefaultThemeDir is the themes directory in the aragomodoro config
+// directory.
+func DefaultThemeDir() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("sound: %w", err)
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// LoadThemes returns the built-in themes together with the .yaml, .yml
+// and .json themes and the audio files in dir, which replace built-ins of
+// the same name. A missing dir adds nothing. Files that cannot be read are
+// reported in the error, and the registry holds every other theme all the
+// same.
+func LoadThemes(dir string) (*Registry, error) {
+	r := &Registry{themes: make(map[string]Theme)}
+	for name, theme := range Builtins().themes {
+		r.themes[name] = theme
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("sound: %w", err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			if !isAudioFile(entry.Name()) {
+				continue
+			}
+		}
+		theme, err := LoadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.themes[theme.Name] = theme
+	}
+	return r, errors.Join(errs...)
+}
+
+// themeName is a theme file's name without its extension.
+func themeName(file string) string {
+	return strings.TrimSuffix(file, filepath.Ext(file))
+}
+
+// Cues are the themes played when a focus phase, a break and a long break
+// end.
+type Cues struct {
+	Focus, Break, LongBreak Theme
+}
+
+// TerminalCues are what a terminal session plays by default.
+func TerminalCues() Cues {
+	return Cues{Focus: builtin("aragorn"), Break: builtin("mount-doom"), LongBreak: builtin("minas-tirith")}
+}
+
+// WebCues are the softer themes the web interface plays by default.
+func WebCues() Cues {
+	return Cues{Focus: builtin("soft-focus"), Break: builtin("soft-break"), LongBreak: builtin("soft-long-break")}
+}
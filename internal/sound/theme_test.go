@@ -0,0 +1,230 @@
+package sound
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoteFrequency(t *testing.T) {
+	tests := map[string]float64{
+		"A4":  440.00,
+		"a4":  440.00,
+		"G4":  392.00,
+		"C5":  523.25,
+		"D#3": 155.56,
+		"Eb3": 155.56,
+		"Bb3": 233.08,
+		"C-1": 8.18,
+	}
+	for name, want := range tests {
+		got, err := noteFrequency(name)
+		if err != nil {
+			t.Errorf("noteFrequency(%q): %v", name, err)
+			continue
+		}
+		if math.Abs(got-want) > 0.01 {
+			t.Errorf("noteFrequency(%q) = %.2f, want %.2f", name, got, want)
+		}
+	}
+	for _, name := range []string{"H4", "C", "C##4", "C10", ""} {
+		if _, err := noteFrequency(name); err == nil {
+			t.Errorf("noteFrequency(%q) should fail", name)
+		}
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme("shire", []byte("gap: 10ms\nnotes:\n  - G4 300ms\n  - rest 100ms\n  - C5 1s\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []note{{"G4", []float64{392}, 300 * time.Millisecond}, {"rest", nil, 100 * time.Millisecond}, {"C5", []float64{523.25}, time.Second}}
+	if theme.Name != "shire" || theme.gap != 10*time.Millisecond || len(theme.notes) != len(want) {
+		t.Fatalf("ParseTheme = %+v", theme)
+	}
+	if got := strings.Join(theme.Notes(), ", "); got != "G4 300ms, rest 100ms, C5 1s" {
+		t.Errorf("Notes() = %s", got)
+	}
+	for i, n := range theme.notes {
+		if n.name != want[i].name || !sameFreqs(n.freqs, want[i].freqs) || n.duration != want[i].duration {
+			t.Errorf("note %d = %+v, want %+v", i, n, want[i])
+		}
+	}
+	if got := theme.Duration(); got != 1430*time.Millisecond {
+		t.Errorf("Duration() = %v, want 1.43s", got)
+	}
+}
+
+// sameFreqs compares frequencies to the hundredth of a hertz.
+func sameFreqs(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 0.01 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseTheme_Synth(t *testing.T) {
+	theme, err := ParseTheme("shire", []byte(`
+wave: Square
+tempo: 120
+gap: 1/4
+envelope: {attack: 10ms, sustain: 0.5}
+notes: [C4+E4+G4 1/2, rest 1.5, A4 250ms]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameFreqs(theme.notes[0].freqs, []float64{261.63, 329.63, 392}) || theme.notes[0].duration != 250*time.Millisecond {
+		t.Errorf("chord = %+v", theme.notes[0])
+	}
+	if theme.notes[1].duration != 750*time.Millisecond || theme.notes[2].duration != 250*time.Millisecond || theme.gap != 125*time.Millisecond {
+		t.Errorf("durations = %+v, gap %s", theme.notes, theme.gap)
+	}
+	if theme.voice.wave(0.25) != 0.5 {
+		t.Error("wave should be square")
+	}
+	want := envelope{attack: 10 * time.Millisecond, decay: defaultEnvelope.decay, sustain: 0.5, release: defaultEnvelope.release}
+	if theme.voice.envelope != want {
+		t.Errorf("envelope = %+v, want %+v", theme.voice.envelope, want)
+	}
+}
+
+func TestParseTheme_JSON(t *testing.T) {
+	theme, err := ParseTheme("beep", []byte(`{"notes": ["A4 100ms", "rest 50ms"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.gap != defaultGap || len(theme.notes) != 2 {
+		t.Errorf("ParseTheme = %+v", theme)
+	}
+}
+
+func TestParseTheme_Errors(t *testing.T) {
+	tests := map[string]string{
+		"not yaml":      "notes: [",
+		"no notes":      "gap: 10ms\n",
+		"bad gap":       "gap: soon\nnotes: [A4 100ms]\n",
+		"bad note":      "notes: [H4 100ms]\n",
+		"no duration":   "notes: [A4]\n",
+		"bad duration":  "notes: [A4 long]\n",
+		"zero duration": "notes: [rest 0s]\n",
+		"beats":         "notes: [A4 1/2]\n",
+		"bad fraction":  "tempo: 60\nnotes: [A4 1/0]\n",
+		"bad chord":     "notes: [C4+H4 1s]\n",
+		"bad tempo":     "tempo: -60\nnotes: [A4 1s]\n",
+		"bad wave":      "wave: organ\nnotes: [A4 1s]\n",
+		"bad sustain":   "envelope: {sustain: 2}\nnotes: [A4 1s]\n",
+		"bad attack":    "envelope: {attack: slow}\nnotes: [A4 1s]\n",
+	}
+	for name, data := range tests {
+		if _, err := ParseTheme(name, []byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else if !strings.Contains(err.Error(), "theme "+name) {
+			t.Errorf("%s: error %q should name the theme", name, err)
+		}
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	names := Builtins().Names()
+	want := []string{"aragorn", "elves", "hobbits", "minas-tirith", "mount-doom", "soft-break", "soft-focus", "soft-long-break"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Names() = %v, want %v", names, want)
+	}
+
+	aragorn, err := Builtins().Get("aragorn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aragorn.notes) != 7 || !sameFreqs(aragorn.notes[0].freqs[:1], []float64{196}) || aragorn.notes[3].duration != 600*time.Millisecond {
+		t.Errorf("aragorn = %+v", aragorn.notes)
+	}
+
+	if _, err := Builtins().Get("sauron"); err == nil || !strings.Contains(err.Error(), "aragorn") {
+		t.Errorf("Get(sauron) error = %v, want one listing the themes", err)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"shire.yaml":   "notes: [G4 100ms]\n",
+		"aragorn.json": `{"notes": ["A4 100ms"]}`,
+		"broken.yml":   "notes: [H9 1ms]\n",
+		"README.md":    "not a theme",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	themes, err := LoadThemes(dir)
+	if err == nil || !strings.Contains(err.Error(), "theme broken") {
+		t.Errorf("LoadThemes error = %v, want the broken theme reported", err)
+	}
+	if _, err := themes.Get("shire"); err != nil {
+		t.Errorf("user theme missing: %v", err)
+	}
+	if aragorn, _ := themes.Get("aragorn"); len(aragorn.notes) != 1 {
+		t.Errorf("user theme should replace the built-in, got %+v", aragorn.notes)
+	}
+	if _, err := themes.Get("elves"); err != nil {
+		t.Errorf("built-in theme missing: %v", err)
+	}
+	if builtin, _ := Builtins().Get("aragorn"); len(builtin.notes) != 7 {
+		t.Error("LoadThemes should leave the built-ins alone")
+	}
+}
+
+func TestLoadThemes_MissingDir(t *testing.T) {
+	themes, err := LoadThemes(filepath.Join(t.TempDir(), "themes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(themes.Names()) != len(Builtins().Names()) {
+		t.Errorf("Names() = %v, want the built-ins", themes.Names())
+	}
+}
+
+func TestDefaultThemeDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	dir, err := DefaultThemeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != "/tmp/xdg/aragomodoro/themes" {
+		t.Errorf("DefaultThemeDir() = %q", dir)
+	}
+}
+
+func TestCues(t *testing.T) {
+	recorder := record(t)
+	for _, cues := range []Cues{TerminalCues(), WebCues()} {
+		for _, theme := range []Theme{cues.Focus, cues.Break, cues.LongBreak} {
+			if len(theme.notes) == 0 {
+				t.Errorf("cue %q has no notes", theme.Name)
+			}
+			if err := theme.Play(); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	if err := (Theme{}).Play(); err != nil {
+		t.Error(err)
+	}
+
+	want := "aragorn,mount-doom,minas-tirith,soft-focus,soft-break,soft-long-break"
+	if got := strings.Join(playedNames(recorder), ","); got != want {
+		t.Errorf("played %s, want %s; an empty theme plays nothing", got, want)
+	}
+}
@@ -1,30 +1,44 @@
 package sound
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestSoftFocusComplete(t *testing.T) {
-	originalMute := Mute
-	Mute = true
-	defer func() { Mute = originalMute }()
-
+	recorder := record(t)
 	SoftFocusComplete()
+
+	played := recorder.Played()
+	if len(played) != 1 || strings.Join(played[0].Notes(), ", ") != "C5 200ms, E5 300ms" {
+		t.Errorf("played %v", playedNames(recorder))
+	}
 }
 
 func TestSoftBreakComplete(t *testing.T) {
-	originalMute := Mute
-	Mute = true
-	defer func() { Mute = originalMute }()
-
+	recorder := record(t)
 	SoftBreakComplete()
+
+	played := recorder.Played()
+	if len(played) != 1 || strings.Join(played[0].Notes(), ", ") != "A4 250ms, F4 350ms" {
+		t.Errorf("played %v", playedNames(recorder))
+	}
+}
+
+func TestSoftLongBreakComplete(t *testing.T) {
+	recorder := record(t)
+	SoftLongBreakComplete()
+
+	played := recorder.Played()
+	if len(played) != 1 || strings.Join(played[0].Notes(), ", ") != "G4 250ms, C5 250ms, E5 400ms" {
+		t.Errorf("played %v", playedNames(recorder))
+	}
 }
 
 func TestSoftSoundsExecution(t *testing.T) {
-	originalMute := Mute
-	Mute = true
-	defer func() { Mute = originalMute }()
+	previous := SetOutput(Null{})
+	defer SetOutput(previous)
 
 	tests := []struct {
 		name string
@@ -32,6 +46,7 @@ func TestSoftSoundsExecution(t *testing.T) {
 	}{
 		{"SoftFocusComplete", SoftFocusComplete},
 		{"SoftBreakComplete", SoftBreakComplete},
+		{"SoftLongBreakComplete", SoftLongBreakComplete},
 	}
 
 	for _, tt := range tests {
@@ -41,16 +56,15 @@ func TestSoftSoundsExecution(t *testing.T) {
 			duration := time.Since(start)
 
 			if duration > 100*time.Millisecond {
-				t.Errorf("Function %s took too long when muted: %v", tt.name, duration)
+				t.Errorf("Function %s took too long with no output: %v", tt.name, duration)
 			}
 		})
 	}
 }
 
 func BenchmarkSoftFocusComplete(b *testing.B) {
-	originalMute := Mute
-	Mute = true
-	defer func() { Mute = originalMute }()
+	previous := SetOutput(Null{})
+	defer SetOutput(previous)
 
 	for i := 0; i < b.N; i++ {
 		SoftFocusComplete()
@@ -58,9 +72,8 @@ func BenchmarkSoftFocusComplete(b *testing.B) {
 }
 
 func BenchmarkSoftBreakComplete(b *testing.B) {
-	originalMute := Mute
-	Mute = true
-	defer func() { Mute = originalMute }()
+	previous := SetOutput(Null{})
+	defer SetOutput(previous)
 
 	for i := 0; i < b.N; i++ {
 		SoftBreakComplete()
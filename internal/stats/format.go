@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+var Formats = []string{FormatTable, FormatJSON, FormatCSV}
+
+// ValidateFormat checks that reports can be written in format.
+func ValidateFormat(format string) error {
+	for _, known := range Formats {
+		if format == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q, expected one of %v", format, Formats)
+}
+
+// Write prints the reports in the given format.
+func Write(w io.Writer, format string, reports []Report) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, reports)
+	case FormatJSON:
+		return writeJSON(w, reports)
+	case FormatCSV:
+		return writeCSV(w, reports)
+	}
+	return ValidateFormat(format)
+}
+
+func writeTable(w io.Writer, reports []Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PERIOD\tFROM\tTO\tFOCUS\tPOMODOROS\tCOMPLETION\tSTREAK\tLONGEST STREAK")
+	for _, r := range reports {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%d/%d\t%.0f%%\t%s\t%s\n",
+			r.Period.Name,
+			r.Period.From.Format(dateLayout),
+			r.Period.LastDay().Format(dateLayout),
+			r.FocusTime.Round(time.Second),
+			r.CompletedPomodoros, r.StartedPomodoros,
+			r.CompletionRate*100,
+			days(r.CurrentStreak),
+			days(r.LongestStreak),
+		)
+	}
+	return tw.Flush()
+}
+
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+type jsonReport struct {
+	Period             string  `json:"period"`
+	From               string  `json:"from"`
+	To                 string  `json:"to"`
+	FocusSeconds       int     `json:"focusSeconds"`
+	CompletedPomodoros int     `json:"completedPomodoros"`
+	StartedPomodoros   int     `json:"startedPomodoros"`
+	CompletionRate     float64 `json:"completionRate"`
+	CurrentStreak      int     `json:"currentStreak"`
+	LongestStreak      int     `json:"longestStreak"`
+}
+
+func writeJSON(w io.Writer, reports []Report) error {
+	out := make([]jsonReport, 0, len(reports))
+	for _, r := range reports {
+		out = append(out, jsonReport{
+			Period:             r.Period.Name,
+			From:               r.Period.From.Format(dateLayout),
+			To:                 r.Period.LastDay().Format(dateLayout),
+			FocusSeconds:       int(r.FocusTime.Round(time.Second) / time.Second),
+			CompletedPomodoros: r.CompletedPomodoros,
+			StartedPomodoros:   r.StartedPomodoros,
+			CompletionRate:     r.CompletionRate,
+			CurrentStreak:      r.CurrentStreak,
+			LongestStreak:      r.LongestStreak,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func writeCSV(w io.Writer, reports []Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"period", "from", "to", "focus_seconds", "completed_pomodoros", "started_pomodoros", "completion_rate", "current_streak", "longest_streak"})
+	for _, r := range reports {
+		cw.Write([]string{
+			r.Period.Name,
+			r.Period.From.Format(dateLayout),
+			r.Period.LastDay().Format(dateLayout),
+			strconv.Itoa(int(r.FocusTime.Round(time.Second) / time.Second)),
+			strconv.Itoa(r.CompletedPomodoros),
+			strconv.Itoa(r.StartedPomodoros),
+			strconv.FormatFloat(r.CompletionRate, 'f', 4, 64),
+			strconv.Itoa(r.CurrentStreak),
+			strconv.Itoa(r.LongestStreak),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReports() []Report {
+	return []Report{{
+		Period:             Today(now),
+		FocusTime:          30 * time.Minute,
+		CompletedPomodoros: 1,
+		StartedPomodoros:   2,
+		CompletionRate:     0.5,
+		CurrentStreak:      4,
+		LongestStreak:      1,
+	}}
+}
+
+func TestWrite_Table(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatTable, testReports()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a header and one row, got %q", buf.String())
+	}
+	for _, want := range []string{"today", "2024-01-10", "30m0s", "1/2", "50%", "4 days", "1 day"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("Expected row to contain %q, got %q", want, lines[1])
+		}
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, testReports()); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("Expected one report, got %d", len(out))
+	}
+	expected := map[string]any{
+		"period":             "today",
+		"from":               "2024-01-10",
+		"to":                 "2024-01-10",
+		"focusSeconds":       1800.0,
+		"completedPomodoros": 1.0,
+		"startedPomodoros":   2.0,
+		"completionRate":     0.5,
+		"currentStreak":      4.0,
+		"longestStreak":      1.0,
+	}
+	for key, value := range expected {
+		if out[0][key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, out[0][key])
+		}
+	}
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, testReports()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "period,from,to,focus_seconds,completed_pomodoros,started_pomodoros,completion_rate,current_streak,longest_streak\n" +
+		"today,2024-01-10,2024-01-10,1800,1,2,0.5000,4,1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xml", testReports()); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	for _, format := range Formats {
+		if err := ValidateFormat(format); err != nil {
+			t.Errorf("ValidateFormat(%s): %v", format, err)
+		}
+	}
+	if err := ValidateFormat("xml"); err == nil {
+		t.Error("Expected ValidateFormat to refuse xml")
+	}
+}
@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+const dateLayout = "2006-01-02"
+
+// Period is a span of whole local days; To is the first day after it.
+type Period struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+type Report struct {
+	Period             Period
+	FocusTime          time.Duration
+	CompletedPomodoros int
+	StartedPomodoros   int
+	// CompletionRate is the share of started pomodoros that ran to the end.
+	CompletionRate float64
+	// CurrentStreak counts the days up to the end of the period, or today if
+	// that comes first, with at least one completed pomodoro, looking back
+	// past the period's start;
+	// LongestStreak is the longest such run inside the period.
+	CurrentStreak int
+	LongestStreak int
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func Today(now time.Time) Period {
+	from := startOfDay(now)
+	return Period{Name: "today", From: from, To: from.AddDate(0, 0, 1)}
+}
+
+// ThisWeek runs from Monday to Sunday.
+func ThisWeek(now time.Time) Period {
+	today := startOfDay(now)
+	from := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	return Period{Name: "week", From: from, To: from.AddDate(0, 0, 7)}
+}
+
+// Range covers from through to, both days included.
+func Range(from, to time.Time) Period {
+	return Period{Name: "range", From: startOfDay(from), To: startOfDay(to).AddDate(0, 0, 1)}
+}
+
+// LastDay is the final day included in the period.
+func (p Period) LastDay() time.Time {
+	return p.To.AddDate(0, 0, -1)
+}
+
+func (p Period) contains(t time.Time) bool {
+	return !t.Before(p.From) && t.Before(p.To)
+}
+
+// Build reports on the focus phases that started within period. now marks
+// today, which counts towards the current streak only once it has a
+// completed pomodoro.
+func Build(entries []history.Entry, period Period, now time.Time) Report {
+	report := Report{Period: period}
+	days := make(map[string]bool)
+
+	for _, entry := range entries {
+		if entry.Type != "focus" {
+			continue
+		}
+		start := entry.Start.In(period.From.Location())
+		if entry.Outcome == history.OutcomeCompleted {
+			days[start.Format(dateLayout)] = true
+		}
+		if !period.contains(start) {
+			continue
+		}
+
+		report.FocusTime += entry.Actual
+		report.StartedPomodoros++
+		if entry.Outcome == history.OutcomeCompleted {
+			report.CompletedPomodoros++
+		}
+	}
+
+	if report.StartedPomodoros > 0 {
+		report.CompletionRate = float64(report.CompletedPomodoros) / float64(report.StartedPomodoros)
+	}
+	report.CurrentStreak = currentStreak(days, period, now)
+	report.LongestStreak = longestStreak(days, period)
+	return report
+}
+
+func currentStreak(days map[string]bool, period Period, now time.Time) int {
+	today := startOfDay(now.In(period.From.Location()))
+	day := period.LastDay()
+	if day.After(today) {
+		day = today
+	}
+	// A day that is still going has not broken the streak yet.
+	if !days[day.Format(dateLayout)] && day.Equal(today) {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for days[day.Format(dateLayout)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+func longestStreak(days map[string]bool, period Period) int {
+	longest, run := 0, 0
+	for day := period.From; day.Before(period.To); day = day.AddDate(0, 0, 1) {
+		if days[day.Format(dateLayout)] {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
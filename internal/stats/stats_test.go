@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+)
+
+// Wednesday
+var now = time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+
+func focus(day, hour int, outcome string, actual time.Duration) history.Entry {
+	start := time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	return history.Entry{
+		Start:   start,
+		End:     start.Add(actual),
+		Type:    "focus",
+		Outcome: outcome,
+		Planned: 25 * time.Minute,
+		Actual:  actual,
+	}
+}
+
+func testEntries() []history.Entry {
+	return []history.Entry{
+		focus(5, 9, history.OutcomeCompleted, 25*time.Minute),
+		focus(7, 9, history.OutcomeCompleted, 25*time.Minute),
+		focus(8, 9, history.OutcomeCompleted, 25*time.Minute),
+		focus(9, 9, history.OutcomeCompleted, 25*time.Minute),
+		focus(9, 10, history.OutcomeSkipped, 10*time.Minute),
+		{Start: time.Date(2024, 1, 9, 10, 25, 0, 0, time.UTC), Type: "break", Outcome: history.OutcomeCompleted, Actual: 5 * time.Minute},
+		focus(10, 9, history.OutcomeCompleted, 25*time.Minute),
+		focus(10, 10, history.OutcomeStopped, 5*time.Minute),
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   Period
+		from, to string
+	}{
+		{"Today", Today(now), "2024-01-10", "2024-01-10"},
+		{"ThisWeek", ThisWeek(now), "2024-01-08", "2024-01-14"},
+		{"ThisWeekOnSunday", ThisWeek(time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC)), "2024-01-08", "2024-01-14"},
+		{"Range", Range(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)), "2024-01-01", "2024-01-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if from := tt.period.From.Format(dateLayout); from != tt.from {
+				t.Errorf("Expected from %s, got %s", tt.from, from)
+			}
+			if to := tt.period.LastDay().Format(dateLayout); to != tt.to {
+				t.Errorf("Expected to %s, got %s", tt.to, to)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   Period
+		now      time.Time
+		expected Report
+	}{
+		{
+			name:   "Today",
+			period: Today(now),
+			now:    now,
+			expected: Report{
+				FocusTime:          30 * time.Minute,
+				CompletedPomodoros: 1,
+				StartedPomodoros:   2,
+				CompletionRate:     0.5,
+				CurrentStreak:      4,
+				LongestStreak:      1,
+			},
+		},
+		{
+			name:   "ThisWeek",
+			period: ThisWeek(now),
+			now:    now,
+			expected: Report{
+				FocusTime:          90 * time.Minute,
+				CompletedPomodoros: 3,
+				StartedPomodoros:   5,
+				CompletionRate:     0.6,
+				// The rest of the week has not happened yet
+				CurrentStreak: 4,
+				LongestStreak: 3,
+			},
+		},
+		{
+			name:   "TodayNotStartedYet",
+			period: Today(time.Date(2024, 1, 11, 8, 0, 0, 0, time.UTC)),
+			now:    time.Date(2024, 1, 11, 8, 0, 0, 0, time.UTC),
+			expected: Report{
+				CurrentStreak: 4,
+			},
+		},
+		{
+			name:   "Range",
+			period: Range(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)),
+			now:    now,
+			expected: Report{
+				FocusTime:          75 * time.Minute,
+				CompletedPomodoros: 3,
+				StartedPomodoros:   3,
+				CompletionRate:     1,
+				CurrentStreak:      2,
+				LongestStreak:      2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expected.Period = tt.period
+			report := Build(testEntries(), tt.period, tt.now)
+			if report != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, report)
+			}
+		})
+	}
+}
+
+func TestBuild_NoHistory(t *testing.T) {
+	report := Build(nil, Today(now), now)
+	if report.StartedPomodoros != 0 || report.CompletionRate != 0 || report.CurrentStreak != 0 {
+		t.Errorf("Expected an empty report, got %+v", report)
+	}
+}
@@ -0,0 +1,139 @@
+package statusbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+)
+
+const (
+	FormatPlain  = "plain"
+	FormatTmux   = "tmux"
+	FormatWaybar = "waybar-json"
+	FormatI3bar  = "i3bar"
+)
+
+// Formats lists every format Line accepts.
+var Formats = []string{FormatPlain, FormatTmux, FormatWaybar, FormatI3bar}
+
+var icons = map[string]string{
+	"focus":      "🧭",
+	"break":      "🌿",
+	"long_break": "🍅",
+	"completed":  "🎉",
+}
+
+// colors are the Dracula palette, which reads well on dark bars.
+var colors = map[string]string{
+	"focus":      "#ff5555",
+	"break":      "#50fa7b",
+	"long_break": "#8be9fd",
+	"paused":     "#f1fa8c",
+}
+
+// Icon is the emoji shown for a phase.
+func Icon(phase string) string {
+	return icons[phase]
+}
+
+type waybarOutput struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip,omitempty"`
+	Class      string `json:"class"`
+	Alt        string `json:"alt"`
+	Percentage int    `json:"percentage"`
+}
+
+type i3barBlock struct {
+	Name      string `json:"name"`
+	FullText  string `json:"full_text"`
+	ShortText string `json:"short_text,omitempty"`
+	Color     string `json:"color,omitempty"`
+}
+
+// Line renders status as one line in format. A session that is not
+// running renders as empty text, which bars take as a cue to hide.
+func Line(format string, status control.Status) (string, error) {
+	idle := !status.Active
+	text := ""
+	if !idle {
+		text = fmt.Sprintf("%s %s %d/%d", Icon(status.Phase), Clock(status.Remaining), status.Cycle, status.RepeatCount)
+		if status.Paused {
+			text = "⏸️ " + text
+		}
+	}
+
+	switch format {
+	case FormatPlain:
+		return text, nil
+	case FormatTmux:
+		if idle {
+			return "", nil
+		}
+		return fmt.Sprintf("#[fg=%s]%s#[default]", color(status), text), nil
+	case FormatWaybar:
+		out := waybarOutput{Text: text, Class: "idle", Alt: "idle"}
+		if !idle {
+			out.Class = class(status)
+			out.Alt = status.Phase
+			out.Tooltip = tooltip(status)
+			out.Percentage = percentage(status)
+		}
+		return marshal(out)
+	case FormatI3bar:
+		block := i3barBlock{Name: "aragomodoro", FullText: text}
+		if !idle {
+			block.ShortText = Clock(status.Remaining)
+			block.Color = color(status)
+		}
+		return marshal(block)
+	default:
+		return "", fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Clock formats seconds as mm:ss, or h:mm:ss from an hour up.
+func Clock(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+func class(status control.Status) string {
+	if status.Paused {
+		return "paused"
+	}
+	return status.Phase
+}
+
+func color(status control.Status) string {
+	return colors[class(status)]
+}
+
+func tooltip(status control.Status) string {
+	phase := strings.ReplaceAll(status.Phase, "_", " ")
+	tip := fmt.Sprintf("%s%s, pomodoro %d of %d", strings.ToUpper(phase[:1]), phase[1:], status.Cycle, status.RepeatCount)
+	if status.Task != "" {
+		tip += "\n📝 " + status.Task
+	}
+	return tip
+}
+
+// percentage is how much of the phase has gone by.
+func percentage(status control.Status) int {
+	if status.Duration <= 0 {
+		return 0
+	}
+	return (status.Duration - status.Remaining) * 100 / status.Duration
+}
+
+func marshal(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
@@ -0,0 +1,73 @@
+package statusbar
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+)
+
+var focus = control.Status{Active: true, Phase: "focus", Cycle: 2, RepeatCount: 4, Duration: 1500, Remaining: 750, Task: "write RFC"}
+
+func TestLine(t *testing.T) {
+	paused := focus
+	paused.Paused = true
+	longBreak := control.Status{Active: true, Phase: "long_break", Cycle: 4, RepeatCount: 4, Duration: 900, Remaining: 900}
+
+	tests := []struct {
+		name     string
+		format   string
+		status   control.Status
+		expected string
+	}{
+		{"Plain", FormatPlain, focus, "🧭 12:30 2/4"},
+		{"PlainPaused", FormatPlain, paused, "⏸️ 🧭 12:30 2/4"},
+		{"PlainIdle", FormatPlain, control.Status{}, ""},
+		{"Tmux", FormatTmux, focus, "#[fg=#ff5555]🧭 12:30 2/4#[default]"},
+		{"TmuxPaused", FormatTmux, paused, "#[fg=#f1fa8c]⏸️ 🧭 12:30 2/4#[default]"},
+		{"TmuxIdle", FormatTmux, control.Status{Phase: "completed", Cycle: 4, RepeatCount: 4}, ""},
+		{"Waybar", FormatWaybar, focus, `{"text":"🧭 12:30 2/4","tooltip":"Focus, pomodoro 2 of 4\n📝 write RFC","class":"focus","alt":"focus","percentage":50}`},
+		{"WaybarLongBreak", FormatWaybar, longBreak, `{"text":"🍅 15:00 4/4","tooltip":"Long break, pomodoro 4 of 4","class":"long_break","alt":"long_break","percentage":0}`},
+		{"WaybarIdle", FormatWaybar, control.Status{}, `{"text":"","class":"idle","alt":"idle","percentage":0}`},
+		{"I3bar", FormatI3bar, focus, `{"name":"aragomodoro","full_text":"🧭 12:30 2/4","short_text":"12:30","color":"#ff5555"}`},
+		{"I3barIdle", FormatI3bar, control.Status{}, `{"name":"aragomodoro","full_text":""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, err := Line(tt.format, tt.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, line)
+			}
+			if tt.format == FormatWaybar || tt.format == FormatI3bar {
+				if !json.Valid([]byte(line)) {
+					t.Errorf("Expected valid JSON, got %s", line)
+				}
+			}
+		})
+	}
+}
+
+func TestLine_UnknownFormat(t *testing.T) {
+	if _, err := Line("lemonbar", focus); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestClock(t *testing.T) {
+	tests := map[int]string{
+		0:    "00:00",
+		59:   "00:59",
+		750:  "12:30",
+		3600: "1:00:00",
+		3725: "1:02:05",
+	}
+	for seconds, expected := range tests {
+		if got := Clock(seconds); got != expected {
+			t.Errorf("Clock(%d) = %q, want %q", seconds, got, expected)
+		}
+	}
+}
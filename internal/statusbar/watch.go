@@ -0,0 +1,47 @@
+package statusbar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+)
+
+// Watch polls fetch every interval and writes a line in format whenever
+// the output changes, until ctx is done. For i3bar the lines are wrapped
+// in the protocol's header and endless array.
+func Watch(ctx context.Context, w io.Writer, format string, interval time.Duration, fetch func() control.Status) error {
+	if format == FormatI3bar {
+		if _, err := fmt.Fprint(w, "{\"version\":1}\n[\n"); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := ""
+	for first := true; ; first = false {
+		line, err := Line(format, fetch())
+		if err != nil {
+			return err
+		}
+		if first || line != last {
+			last = line
+			if format == FormatI3bar {
+				line = "[" + line + "],"
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package statusbar
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+)
+
+// scriptedFetch returns each status in turn, then cancels the watch.
+func scriptedFetch(cancel context.CancelFunc, statuses ...control.Status) func() control.Status {
+	i := 0
+	return func() control.Status {
+		status := statuses[i]
+		if i++; i == len(statuses) {
+			cancel()
+			i--
+		}
+		return status
+	}
+}
+
+func TestWatch_WritesChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	later := focus
+	later.Remaining = 749
+
+	var out bytes.Buffer
+	fetch := scriptedFetch(cancel, focus, focus, later, control.Status{})
+	if err := Watch(ctx, &out, FormatPlain, time.Millisecond, fetch); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "🧭 12:30 2/4\n🧭 12:29 2/4\n\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestWatch_I3bar(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var out bytes.Buffer
+	if err := Watch(ctx, &out, FormatI3bar, time.Millisecond, scriptedFetch(cancel, focus)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != `{"version":1}` || lines[1] != "[" {
+		t.Fatalf("Expected the i3bar header and one status line, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[2], `[{"name":"aragomodoro"`) || !strings.HasSuffix(lines[2], "}],") {
+		t.Errorf("Unexpected status line %q", lines[2])
+	}
+}
+
+func TestWatch_UnknownFormat(t *testing.T) {
+	err := Watch(context.Background(), &bytes.Buffer{}, "lemonbar", time.Millisecond, func() control.Status {
+		return focus
+	})
+	if err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,288 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/filelock"
+	"github.com/aureliomalheiros/aragomodoro/internal/xdg"
+)
+
+const (
+	MaxTitleLength = 200
+	MaxEstimate    = 100
+)
+
+var ErrNotFound = errors.New("task not found")
+
+// Task is something to spend pomodoros on. Estimate is how many it should
+// take and Pomodoros how many focus phases have been completed for it.
+type Task struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	Estimate  int        `json:"estimate"`
+	Pomodoros int        `json:"pomodoros"`
+	Done      bool       `json:"done"`
+	CreatedAt time.Time  `json:"createdAt"`
+	DoneAt    *time.Time `json:"doneAt,omitempty"`
+}
+
+type file struct {
+	NextID int    `json:"nextId"`
+	Tasks  []Task `json:"tasks"`
+}
+
+// Store keeps the task list in a JSON file. Every change reads, edits and
+// replaces the whole file under a lock, so the terminal and web front ends
+// can share it; the file is swapped in by rename, so it is never half
+// written.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// DefaultPath is tasks.json in the aragomodoro data directory.
+func DefaultPath() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", fmt.Errorf("tasks: %w", err)
+	}
+	return filepath.Join(dir, "tasks.json"), nil
+}
+
+// Open returns a store backed by path, creating its directory if needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("tasks: %w", err)
+	}
+	return &Store{path: path, now: time.Now}, nil
+}
+
+// ValidateTitle trims title and checks it fits on the countdown line.
+func ValidateTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", fmt.Errorf("❌ Task title cannot be empty.")
+	}
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return "", fmt.Errorf("❌ Task title should not exceed %d characters.", MaxTitleLength)
+	}
+	if strings.ContainsAny(title, "\r\n") {
+		return "", fmt.Errorf("❌ Task title must fit on a single line.")
+	}
+	return title, nil
+}
+
+func ValidateEstimate(estimate int) error {
+	if estimate <= 0 || estimate > MaxEstimate {
+		return fmt.Errorf("❌ Estimate must be between 1 and %d pomodoros.", MaxEstimate)
+	}
+	return nil
+}
+
+// List returns every task in the order they were added.
+func (s *Store) List() ([]Task, error) {
+	var tasks []Task
+	err := s.view(func(f *file) error {
+		tasks = f.Tasks
+		return nil
+	})
+	return tasks, err
+}
+
+func (s *Store) Get(id int) (Task, error) {
+	var task Task
+	err := s.view(func(f *file) error {
+		t, err := f.find(id)
+		if err == nil {
+			task = *t
+		}
+		return err
+	})
+	return task, err
+}
+
+func (s *Store) Add(title string, estimate int) (Task, error) {
+	title, err := ValidateTitle(title)
+	if err != nil {
+		return Task{}, err
+	}
+	if err := ValidateEstimate(estimate); err != nil {
+		return Task{}, err
+	}
+
+	var task Task
+	err = s.update(func(f *file) error {
+		f.NextID++
+		task = Task{ID: f.NextID, Title: title, Estimate: estimate, CreatedAt: s.now().Round(0)}
+		f.Tasks = append(f.Tasks, task)
+		return nil
+	})
+	return task, err
+}
+
+// Update applies change to the task with the given id and saves it unless
+// change returns an error.
+func (s *Store) Update(id int, change func(*Task) error) (Task, error) {
+	var task Task
+	err := s.update(func(f *file) error {
+		t, err := f.find(id)
+		if err != nil {
+			return err
+		}
+		if err := change(t); err != nil {
+			return err
+		}
+		task = *t
+		return nil
+	})
+	return task, err
+}
+
+func (s *Store) SetEstimate(id, estimate int) (Task, error) {
+	if err := ValidateEstimate(estimate); err != nil {
+		return Task{}, err
+	}
+	return s.Update(id, func(t *Task) error {
+		t.Estimate = estimate
+		return nil
+	})
+}
+
+// SetDone marks the task done, or open again.
+func (s *Store) SetDone(id int, done bool) (Task, error) {
+	return s.Update(id, func(t *Task) error {
+		t.Done = done
+		t.DoneAt = nil
+		if done {
+			now := s.now().Round(0)
+			t.DoneAt = &now
+		}
+		return nil
+	})
+}
+
+// AddPomodoro counts one more completed focus phase for the task.
+func (s *Store) AddPomodoro(id int) (Task, error) {
+	return s.Update(id, func(t *Task) error {
+		t.Pomodoros++
+		return nil
+	})
+}
+
+func (s *Store) Delete(id int) error {
+	return s.update(func(f *file) error {
+		for i := range f.Tasks {
+			if f.Tasks[i].ID == id {
+				f.Tasks = append(f.Tasks[:i], f.Tasks[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: #%d", ErrNotFound, id)
+	})
+}
+
+func (f *file) find(id int) (*Task, error) {
+	for i := range f.Tasks {
+		if f.Tasks[i].ID == id {
+			return &f.Tasks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: #%d", ErrNotFound, id)
+}
+
+func (s *Store) view(read func(*file) error) error {
+	return s.locked(false, func() error {
+		f, err := s.load()
+		if err != nil {
+			return err
+		}
+		return read(f)
+	})
+}
+
+func (s *Store) update(change func(*file) error) error {
+	return s.locked(true, func() error {
+		f, err := s.load()
+		if err != nil {
+			return err
+		}
+		if err := change(f); err != nil {
+			return err
+		}
+		return s.save(f)
+	})
+}
+
+// locked runs fn holding the store's lock file, which stays put while the
+// task file itself is replaced.
+func (s *Store) locked(exclusive bool, fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, err := os.OpenFile(s.path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+	defer lock.Close()
+
+	if err := filelock.Lock(lock, exclusive); err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+	defer filelock.Unlock(lock)
+
+	return fn()
+}
+
+func (s *Store) load() (*file, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &file{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tasks: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("tasks: %s: %w", s.path, err)
+	}
+	return &f, nil
+}
+
+func (s *Store) save(f *file) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tasks-*.json")
+	if err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("tasks: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("tasks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "data", "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return epoch }
+	return store
+}
+
+func TestStore_AddAndList(t *testing.T) {
+	store := newTestStore(t)
+
+	if tasks, err := store.List(); err != nil || len(tasks) != 0 {
+		t.Fatalf("Expected an empty list, got %v, %v", tasks, err)
+	}
+
+	first, err := store.Add("  write RFC  ", 4)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if first.ID != 1 || first.Title != "write RFC" || first.Estimate != 4 || !first.CreatedAt.Equal(epoch) {
+		t.Errorf("Unexpected task %+v", first)
+	}
+	second, err := store.Add("review PRs", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != first.ID || tasks[1].ID != second.ID {
+		t.Errorf("Expected both tasks in order, got %+v", tasks)
+	}
+}
+
+func TestStore_Changes(t *testing.T) {
+	store := newTestStore(t)
+	task, err := store.Add("write RFC", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if task, err = store.SetEstimate(task.ID, 5); err != nil || task.Estimate != 5 {
+		t.Errorf("SetEstimate: got %+v, %v", task, err)
+	}
+	store.AddPomodoro(task.ID)
+	if task, err = store.AddPomodoro(task.ID); err != nil || task.Pomodoros != 2 {
+		t.Errorf("AddPomodoro: got %+v, %v", task, err)
+	}
+	if task, err = store.SetDone(task.ID, true); err != nil || !task.Done || task.DoneAt == nil {
+		t.Errorf("SetDone: got %+v, %v", task, err)
+	}
+	if task, err = store.SetDone(task.ID, false); err != nil || task.Done || task.DoneAt != nil {
+		t.Errorf("SetDone(false): got %+v, %v", task, err)
+	}
+
+	got, err := store.Get(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Estimate != 5 || got.Pomodoros != 2 || got.Done {
+		t.Errorf("Changes were not saved: %+v", got)
+	}
+}
+
+func TestStore_DeleteKeepsIDsUnique(t *testing.T) {
+	store := newTestStore(t)
+	first, _ := store.Add("one", 1)
+	store.Add("two", 1)
+
+	if err := store.Delete(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+
+	third, _ := store.Add("three", 1)
+	if third.ID != 3 {
+		t.Errorf("Expected IDs not to be reused, got %d", third.ID)
+	}
+}
+
+func TestStore_NotFound(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.Get(7); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get: expected ErrNotFound, got %v", err)
+	}
+	if _, err := store.AddPomodoro(7); !errors.Is(err, ErrNotFound) {
+		t.Errorf("AddPomodoro: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Delete(7); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_Validation(t *testing.T) {
+	store := newTestStore(t)
+
+	tests := []struct {
+		name     string
+		title    string
+		estimate int
+	}{
+		{"EmptyTitle", "   ", 1},
+		{"LongTitle", strings.Repeat("a", MaxTitleLength+1), 1},
+		{"MultilineTitle", "one\ntwo", 1},
+		{"ZeroEstimate", "task", 0},
+		{"HugeEstimate", "task", MaxEstimate + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := store.Add(tt.title, tt.estimate); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+
+	if tasks, _ := store.List(); len(tasks) != 0 {
+		t.Errorf("Invalid tasks should not be saved, got %+v", tasks)
+	}
+}
+
+func TestStore_ConcurrentUpdates(t *testing.T) {
+	store := newTestStore(t)
+	task, _ := store.Add("write RFC", 10)
+	// A second store on the same file stands in for another process.
+	other, err := Open(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(s *Store) {
+			defer wg.Done()
+			if _, err := s.AddPomodoro(task.ID); err != nil {
+				t.Error(err)
+			}
+		}([]*Store{store, other}[i%2])
+	}
+	wg.Wait()
+
+	got, err := store.Get(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Pomodoros != 30 {
+		t.Errorf("Expected 30 pomodoros, got %d", got.Pomodoros)
+	}
+}
+
+func TestStore_CorruptFile(t *testing.T) {
+	store := newTestStore(t)
+	if err := os.WriteFile(store.path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.List(); err == nil {
+		t.Error("Expected an error for a corrupt file")
+	}
+	if _, err := store.Add("task", 1); err == nil {
+		t.Error("A corrupt file should not be overwritten")
+	}
+}
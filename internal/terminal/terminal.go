@@ -0,0 +1,5 @@
+package terminal
+
+import "errors"
+
+var ErrUnsupported = errors.New("terminal: not supported on this platform")
@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package terminal
+
+import "os"
+
+func IsTerminal(fd int) bool {
+	return false
+}
+
+func EnableCbreak(fd int) (func() error, error) {
+	return nil, ErrUnsupported
+}
+
+func Size(fd int) (int, int, error) {
+	return 0, 0, ErrUnsupported
+}
+
+func NotifyResize(c chan<- os.Signal) {}
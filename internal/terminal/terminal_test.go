@@ -0,0 +1,42 @@
+package terminal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsTerminal_NotATTY(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "tty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(int(f.Fd())) {
+		t.Error("A regular file should not be reported as a terminal")
+	}
+}
+
+func TestEnableCbreak_NotATTY(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "tty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := EnableCbreak(int(f.Fd())); err == nil {
+		t.Error("EnableCbreak should fail on a regular file")
+	}
+}
+
+func TestSize_NotATTY(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "tty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, _, err := Size(int(f.Fd())); err == nil {
+		t.Error("Size should fail on a regular file")
+	}
+}
@@ -0,0 +1,172 @@
+package todotxt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Line is an item bound to its place in a todo.txt file, written as
+// path:number with numbers starting at 1.
+type Line struct {
+	Path   string
+	Number int
+	Item   Item
+}
+
+// ParseRef splits a path:number reference.
+func ParseRef(ref string) (string, int, error) {
+	i := strings.LastIndex(ref, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid todo.txt reference %q, expected file:line", ref)
+	}
+	number, err := strconv.Atoi(ref[i+1:])
+	if err != nil || number <= 0 {
+		return "", 0, fmt.Errorf("invalid line number in %q", ref)
+	}
+	return ref[:i], number, nil
+}
+
+// Open reads the line at number from the file at path.
+func Open(path string, number int) (*Line, error) {
+	lines, _, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	if number > len(lines) {
+		return nil, fmt.Errorf("%s has no line %d", path, number)
+	}
+	item := Parse(lines[number-1])
+	if item.Text == "" {
+		return nil, fmt.Errorf("%s:%d is empty", path, number)
+	}
+	return &Line{Path: path, Number: number, Item: item}, nil
+}
+
+// ReadFile reads every item of a todo.txt file, keyed by line number;
+// blank lines are left out.
+func ReadFile(path string) ([]Line, error) {
+	lines, _, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	var items []Line
+	for n, text := range lines {
+		if item := Parse(text); item.Text != "" {
+			items = append(items, Line{Path: path, Number: n + 1, Item: item})
+		}
+	}
+	return items, nil
+}
+
+// AddPomodoro counts one more finished focus phase in the line's
+// pomodoros tag. The file is read again first, since it may have been
+// edited meanwhile; when the line has moved it is looked up by its text.
+func (l *Line) AddPomodoro() error {
+	lines, eol, err := readLines(l.Path)
+	if err != nil {
+		return err
+	}
+
+	n, err := l.find(lines)
+	if err != nil {
+		return err
+	}
+	item := Parse(lines[n])
+	value, _ := item.Tag(PomodorosKey)
+	count, _ := strconv.Atoi(value)
+	item.SetTag(PomodorosKey, strconv.Itoa(count+1))
+	lines[n] = item.String()
+
+	if err := writeLines(l.Path, lines, eol); err != nil {
+		return err
+	}
+	l.Number, l.Item = n+1, item
+	return nil
+}
+
+func (l *Line) find(lines []string) (int, error) {
+	if l.Number <= len(lines) && sameTask(Parse(lines[l.Number-1]), l.Item) {
+		return l.Number - 1, nil
+	}
+	found := -1
+	for n, text := range lines {
+		if sameTask(Parse(text), l.Item) {
+			if found >= 0 {
+				return 0, fmt.Errorf("%s:%d has moved and more than one line matches it", l.Path, l.Number)
+			}
+			found = n
+		}
+	}
+	if found < 0 {
+		return 0, fmt.Errorf("%s:%d is gone", l.Path, l.Number)
+	}
+	return found, nil
+}
+
+// sameTask compares the descriptions of two items, ignoring the
+// pomodoros tag so a line is still found after it has been counted.
+func sameTask(a, b Item) bool {
+	return a.Text != "" && withoutTag(a.Text, PomodorosKey) == withoutTag(b.Text, PomodorosKey)
+}
+
+func withoutTag(text, key string) string {
+	var words []string
+	for _, word := range strings.Fields(text) {
+		if k, _, ok := splitTag(word); !ok || k != key {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// readLines splits a file into lines, noting whether it uses CRLF line
+// endings so they can be written back the same way.
+func readLines(path string) ([]string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	text := string(data)
+	eol := "\n"
+	if strings.Contains(text, "\r\n") {
+		eol = "\r\n"
+	}
+	text = strings.TrimSuffix(text, eol)
+	if text == "" {
+		return nil, eol, nil
+	}
+	return strings.Split(text, eol), eol, nil
+}
+
+// writeLines replaces the file by rename, keeping its permissions, so a
+// reader never sees it half written.
+func writeLines(path string, lines []string, eol string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".todo-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(strings.Join(lines, eol) + eol)
+	if err == nil {
+		err = tmp.Chmod(info.Mode().Perm())
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,126 @@
+package todotxt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTodo(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readTodo(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		path    string
+		number  int
+		wantErr bool
+	}{
+		{"todo.txt:3", "todo.txt", 3, false},
+		{"C:/notes/todo.txt:12", "C:/notes/todo.txt", 12, false},
+		{"todo.txt", "", 0, true},
+		{"todo.txt:0", "", 0, true},
+		{"todo.txt:x", "", 0, true},
+		{":3", "", 0, true},
+	}
+	for _, tt := range tests {
+		path, number, err := ParseRef(tt.ref)
+		if (err != nil) != tt.wantErr || path != tt.path || number != tt.number {
+			t.Errorf("ParseRef(%q) = %q, %d, %v", tt.ref, path, number, err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := writeTodo(t, "(A) Write RFC +work\n\nx 2024-01-02 Old chore\n")
+
+	line, err := Open(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line.Item.Priority != "A" || line.Item.Label() != "(A) Write RFC +work" {
+		t.Errorf("Unexpected item %+v", line.Item)
+	}
+	if _, err := Open(path, 2); err == nil {
+		t.Error("Expected an error for a blank line")
+	}
+	if _, err := Open(path, 4); err == nil {
+		t.Error("Expected an error past the end of the file")
+	}
+
+	lines, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[1].Number != 3 || !lines[1].Item.Done {
+		t.Errorf("Unexpected lines %+v", lines)
+	}
+}
+
+func TestLine_AddPomodoro(t *testing.T) {
+	path := writeTodo(t, "Call mom\n(A) Write RFC +work\n")
+	line, err := Open(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := line.AddPomodoro(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readTodo(t, path); got != "Call mom\n(A) Write RFC +work pomodoros:2\n" {
+		t.Errorf("Unexpected file %q", got)
+	}
+
+	// Lines added above the task while the session runs move it down.
+	if err := os.WriteFile(path, []byte("New first line\nCall mom\n(A) Write RFC +work pomodoros:2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := line.AddPomodoro(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTodo(t, path); !strings.HasSuffix(got, "(A) Write RFC +work pomodoros:3\n") || line.Number != 3 {
+		t.Errorf("Expected the moved line counted, got %q at line %d", got, line.Number)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected permissions kept, got %v", info.Mode().Perm())
+	}
+
+	if err := os.WriteFile(path, []byte("Call mom\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := line.AddPomodoro(); err == nil {
+		t.Error("Expected an error once the line is gone")
+	}
+}
+
+func TestLine_AddPomodoroCRLF(t *testing.T) {
+	path := writeTodo(t, "Write RFC\r\nCall mom\r\n")
+	line, err := Open(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := line.AddPomodoro(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTodo(t, path); got != "Write RFC pomodoros:1\r\nCall mom\r\n" {
+		t.Errorf("Expected CRLF line endings kept, got %q", got)
+	}
+}
@@ -0,0 +1,125 @@
+package todotxt
+
+import (
+	"strings"
+	"time"
+)
+
+// PomodorosKey is the tag that counts the focus phases finished for a line.
+const PomodorosKey = "pomodoros"
+
+const dateLayout = "2006-01-02"
+
+// Item is one line of a todo.txt file. Text is the description as written,
+// with its +projects, @contexts and key:value tags in place.
+type Item struct {
+	Done      bool
+	Priority  string
+	Completed time.Time
+	Created   time.Time
+	Text      string
+}
+
+// Parse reads a todo.txt line. Anything that does not fit the format ends
+// up in Text, so every line parses.
+func Parse(line string) Item {
+	var item Item
+	rest := strings.TrimSpace(line)
+
+	if strings.HasPrefix(rest, "x ") {
+		item.Done = true
+		rest = strings.TrimLeft(rest[2:], " ")
+		if date, ok := cutDate(&rest); ok {
+			item.Completed = date
+			item.Created, _ = cutDate(&rest)
+		}
+	} else {
+		if len(rest) >= 4 && rest[0] == '(' && rest[1] >= 'A' && rest[1] <= 'Z' && rest[2] == ')' && rest[3] == ' ' {
+			item.Priority = rest[1:2]
+			rest = strings.TrimLeft(rest[4:], " ")
+		}
+		item.Created, _ = cutDate(&rest)
+	}
+
+	item.Text = rest
+	return item
+}
+
+func cutDate(s *string) (time.Time, bool) {
+	word, rest, _ := strings.Cut(*s, " ")
+	date, err := time.Parse(dateLayout, word)
+	if err != nil {
+		return time.Time{}, false
+	}
+	*s = strings.TrimLeft(rest, " ")
+	return date, true
+}
+
+// String formats the item back into a todo.txt line.
+func (i Item) String() string {
+	var parts []string
+	if i.Done {
+		parts = append(parts, "x")
+		if !i.Completed.IsZero() {
+			parts = append(parts, i.Completed.Format(dateLayout))
+		}
+	} else if i.Priority != "" {
+		parts = append(parts, "("+i.Priority+")")
+	}
+	if !i.Created.IsZero() && (!i.Done || !i.Completed.IsZero()) {
+		parts = append(parts, i.Created.Format(dateLayout))
+	}
+	if i.Text != "" {
+		parts = append(parts, i.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Tag returns the value of the first key:value tag with the given key.
+func (i Item) Tag(key string) (string, bool) {
+	for _, word := range strings.Fields(i.Text) {
+		if k, v, ok := splitTag(word); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// SetTag replaces the value of a key:value tag, adding the tag at the end
+// of the line when it is not there yet.
+func (i *Item) SetTag(key, value string) {
+	words := strings.Split(i.Text, " ")
+	for n, word := range words {
+		if k, _, ok := splitTag(word); ok && k == key {
+			words[n] = key + ":" + value
+			i.Text = strings.Join(words, " ")
+			return
+		}
+	}
+	i.Text = strings.TrimSpace(i.Text + " " + key + ":" + value)
+}
+
+// Label is the task label for a session on the item: its description
+// with the priority in front, keeping +projects and @contexts but leaving
+// out key:value tags.
+func (i Item) Label() string {
+	var words []string
+	if i.Priority != "" {
+		words = append(words, "("+i.Priority+")")
+	}
+	for _, word := range strings.Fields(i.Text) {
+		if _, _, ok := splitTag(word); !ok {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitTag splits a key:value word. URLs are not tags.
+func splitTag(word string) (string, string, bool) {
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" || strings.HasPrefix(value, "//") || strings.ContainsAny(key[:1], "+@") {
+		return "", "", false
+	}
+	return key, value, true
+}
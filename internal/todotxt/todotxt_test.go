@@ -0,0 +1,76 @@
+package todotxt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse(dateLayout, s)
+	return d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Item
+	}{
+		{"Call mom", Item{Text: "Call mom"}},
+		{"(A) Write RFC +aragomodoro @laptop due:2024-02-01", Item{Priority: "A", Text: "Write RFC +aragomodoro @laptop due:2024-02-01"}},
+		{"(B) 2024-01-05 Review PR", Item{Priority: "B", Created: date("2024-01-05"), Text: "Review PR"}},
+		{"x 2024-01-07 2024-01-05 Review PR", Item{Done: true, Completed: date("2024-01-07"), Created: date("2024-01-05"), Text: "Review PR"}},
+		{"x 2024-01-07 Review PR", Item{Done: true, Completed: date("2024-01-07"), Text: "Review PR"}},
+		{"x Review PR", Item{Done: true, Text: "Review PR"}},
+		{"(a) lowercase is not a priority", Item{Text: "(a) lowercase is not a priority"}},
+		{"xylophone practice", Item{Text: "xylophone practice"}},
+		{"   ", Item{}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestItem_StringRoundTrip(t *testing.T) {
+	for _, line := range []string{
+		"Call mom",
+		"(A) Write RFC +aragomodoro @laptop due:2024-02-01",
+		"(B) 2024-01-05 Review PR",
+		"x 2024-01-07 2024-01-05 Review PR pomodoros:3",
+		"x Review PR",
+	} {
+		if got := Parse(line).String(); got != line {
+			t.Errorf("Round trip of %q gave %q", line, got)
+		}
+	}
+}
+
+func TestItem_TagsAndLabel(t *testing.T) {
+	item := Parse("(A) Write RFC +aragomodoro +docs @laptop due:2024-02-01 see https://example.com +")
+
+	if value, ok := item.Tag("due"); !ok || value != "2024-02-01" {
+		t.Errorf("Tag(due) = %q, %v", value, ok)
+	}
+	if _, ok := item.Tag("https"); ok {
+		t.Error("A URL should not be read as a tag")
+	}
+	if got := item.Label(); got != "(A) Write RFC +aragomodoro +docs @laptop see https://example.com +" {
+		t.Errorf("Label() = %q", got)
+	}
+}
+
+func TestItem_SetTag(t *testing.T) {
+	item := Parse("Write RFC @laptop")
+	item.SetTag(PomodorosKey, "1")
+	if item.Text != "Write RFC @laptop pomodoros:1" {
+		t.Errorf("Expected the tag appended, got %q", item.Text)
+	}
+
+	item = Parse("Write RFC pomodoros:2 @laptop")
+	item.SetTag(PomodorosKey, "3")
+	if item.Text != "Write RFC pomodoros:3 @laptop" {
+		t.Errorf("Expected the tag replaced in place, got %q", item.Text)
+	}
+}
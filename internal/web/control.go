@@ -0,0 +1,82 @@
+package web
+
+import (
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
+)
+
+// ControlHandler answers control socket requests with the sessions the web
+// interface runs, so a daemon and its page share one timer.
+func ControlHandler() control.Handler {
+	return controlHandler{tm: timerManager}
+}
+
+type controlHandler struct {
+	tm *WebTimerManager
+}
+
+func (h controlHandler) Handle(req control.Request) control.Response {
+	switch req.Command {
+	case control.CommandStart:
+		if req.Session == nil {
+			return control.Errorf("start needs a session")
+		}
+		timerReq := TimerRequest{
+			FocusDuration:     req.Session.Focus,
+			BreakDuration:     req.Session.Break,
+			LongBreakDuration: req.Session.LongBreak,
+			LongBreakEvery:    req.Session.LongBreakEvery,
+			RepeatCount:       req.Session.Repeat,
+			ContinueOnBreak:   req.Session.Continue,
+			Task:              req.Session.Task,
+		}
+		if _, err := h.tm.prepareTimerRequest(&timerReq); err != nil {
+			return control.Errorf("%v", err)
+		}
+		h.tm.startTimerSession(timerReq)
+	case control.CommandStop:
+		h.tm.stopTimerSession()
+	case control.CommandPause:
+		return h.drive((*pomodoro.Timer).Pause)
+	case control.CommandResume:
+		return h.drive((*pomodoro.Timer).Resume)
+	case control.CommandSkip:
+		return h.drive((*pomodoro.Timer).Skip)
+	case control.CommandStatus:
+	default:
+		return control.Errorf("unknown command %q", req.Command)
+	}
+
+	status := h.tm.status()
+	return control.Response{OK: true, Status: &status}
+}
+
+func (h controlHandler) drive(action func(*pomodoro.Timer)) control.Response {
+	if !h.tm.controlTimerSession(action) {
+		return control.Errorf("no session is running")
+	}
+	status := h.tm.status()
+	return control.Response{OK: true, Status: &status}
+}
+
+// status reports the current session the way the control protocol does.
+func (tm *WebTimerManager) status() control.Status {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if tm.session == nil {
+		return control.Status{}
+	}
+	session := tm.session
+	return control.Status{
+		Active:      session.Active,
+		Phase:       session.Type,
+		Cycle:       session.CurrentCycle,
+		RepeatCount: session.RepeatCount,
+		Duration:    session.DurationSeconds,
+		Remaining:   session.Remaining,
+		Paused:      session.Paused,
+		Task:        session.Task,
+		EndsAt:      session.EndsAt,
+	}
+}
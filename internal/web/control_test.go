@@ -0,0 +1,73 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/gorilla/websocket"
+)
+
+func TestControlHandler(t *testing.T) {
+	sound.SetOutput(sound.Null{})
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	testManager := &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		clock:   fake,
+	}
+	handler := controlHandler{tm: testManager}
+
+	resp := handler.Handle(control.Request{Command: control.CommandStatus})
+	if !resp.OK || resp.Status.Phase != "" || resp.Status.Active {
+		t.Errorf("Expected no session yet, got %+v", resp)
+	}
+	if resp := handler.Handle(control.Request{Command: control.CommandPause}); resp.OK {
+		t.Error("Expected pause to fail without a session")
+	}
+
+	resp = handler.Handle(control.Request{Command: control.CommandStart, Session: &control.Session{Focus: 1, Break: 1, Repeat: 2, Task: " write RFC "}})
+	if !resp.OK {
+		t.Fatalf("Start failed: %s", resp.Error)
+	}
+	if status := resp.Status; !status.Active || status.Phase != "focus" || status.RepeatCount != 2 || status.Remaining != 60 || status.Task != "write RFC" {
+		t.Errorf("Unexpected status after start %+v", status)
+	}
+
+	fake.BlockUntil(1)
+	if resp := handler.Handle(control.Request{Command: control.CommandPause}); !resp.OK || !resp.Status.Paused {
+		t.Errorf("Expected the session paused, got %+v", resp)
+	}
+	if resp := handler.Handle(control.Request{Command: control.CommandResume}); !resp.OK || resp.Status.Paused {
+		t.Errorf("Expected the session running again, got %+v", resp)
+	}
+	if resp := handler.Handle(control.Request{Command: control.CommandSkip}); !resp.OK {
+		t.Errorf("Skip failed: %s", resp.Error)
+	}
+	waitForSession(t, testManager, func(s TimerSession) bool {
+		return s.Type == "break"
+	})
+
+	resp = handler.Handle(control.Request{Command: control.CommandStop})
+	if !resp.OK || resp.Status.Active || resp.Status.EndsAt != nil {
+		t.Errorf("Expected the session stopped, got %+v", resp)
+	}
+}
+
+func TestControlHandler_Invalid(t *testing.T) {
+	handler := controlHandler{tm: &WebTimerManager{clients: make(map[*websocket.Conn]bool)}}
+
+	requests := []control.Request{
+		{Command: control.CommandStart},
+		{Command: control.CommandStart, Session: &control.Session{Focus: 0, Break: 5, Repeat: 1}},
+		{Command: control.CommandStart, Session: &control.Session{Focus: 25, Break: 5, LongBreak: 90, LongBreakEvery: 4, Repeat: 4}},
+		{Command: "dance"},
+	}
+	for _, req := range requests {
+		if resp := handler.Handle(req); resp.OK || resp.Error == "" {
+			t.Errorf("Expected %+v to be rejected, got %+v", req, resp)
+		}
+	}
+}
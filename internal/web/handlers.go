@@ -1,16 +1,23 @@
 package web
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+	"github.com/aureliomalheiros/aragomodoro/internal/config"
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
 	"github.com/aureliomalheiros/aragomodoro/internal/pomodoro"
 	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/aureliomalheiros/aragomodoro/internal/tasks"
 	"github.com/gorilla/websocket"
 )
 
@@ -24,34 +31,77 @@ var upgrader = websocket.Upgrader{
 }
 
 type TimerSession struct {
-	Active       bool   `json:"active"`
-	Type         string `json:"type"`
-	Duration     int    `json:"duration"`
-	Remaining    int    `json:"remaining"`
-	RepeatCount  int    `json:"repeatCount"`
-	CurrentCycle int    `json:"currentCycle"`
+	Active          bool   `json:"active"`
+	Type            string `json:"type"`
+	Duration        int    `json:"duration"`
+	DurationSeconds int    `json:"durationSeconds"`
+	Remaining       int    `json:"remaining"`
+	RepeatCount     int    `json:"repeatCount"`
+	CurrentCycle    int    `json:"currentCycle"`
+	Paused          bool   `json:"paused"`
+	Task            string `json:"task,omitempty"`
+	TaskID          int    `json:"taskId,omitempty"`
+	// EndsAt is when the current phase ends; omitted while paused.
+	EndsAt *time.Time `json:"endsAt,omitempty"`
 }
 
 type WebTimerManager struct {
 	mu        sync.RWMutex
 	session   *TimerSession
+	timer     *pomodoro.Timer
+	clock     clock.Clock
+	history   *history.Store
+	config    *config.Config
+	tasks     *tasks.Store
+	taskID    int
+	sounds    sound.Cues
 	clients   map[*websocket.Conn]bool
 	clientsMu sync.RWMutex
-	stopChan  chan bool
+	// running counts the timers whose goroutine has not returned, so a
+	// shutdown can wait for a stopped phase to be recorded.
+	running sync.WaitGroup
 }
 
 var timerManager = &WebTimerManager{
-	clients:  make(map[*websocket.Conn]bool),
-	stopChan: make(chan bool),
+	sounds:  sound.WebCues(),
+	clients: make(map[*websocket.Conn]bool),
 }
 
 type TimerRequest struct {
-	FocusDuration   int  `json:"focusDuration"`
-	BreakDuration   int  `json:"breakDuration"`
-	RepeatCount     int  `json:"repeatCount"`
-	ContinueOnBreak bool `json:"continueOnBreak"`
+	FocusDuration     int    `json:"focusDuration"`
+	BreakDuration     int    `json:"breakDuration"`
+	LongBreakDuration int    `json:"longBreakDuration"`
+	LongBreakEvery    int    `json:"longBreakEvery"`
+	RepeatCount       int    `json:"repeatCount"`
+	ContinueOnBreak   bool   `json:"continueOnBreak"`
+	Task              string `json:"task,omitempty"`
+	// TaskID binds the session to a task from the task list, whose title
+	// is used when Task is empty.
+	TaskID int `json:"taskId,omitempty"`
 }
 
+// UpdateTimerRequest changes the running session; fields left out stay as
+// they are.
+type UpdateTimerRequest struct {
+	Task *string `json:"task"`
+}
+
+type Profile struct {
+	Name string `json:"name"`
+	TimerRequest
+}
+
+type ProfilesResponse struct {
+	DefaultProfile string    `json:"defaultProfile,omitempty"`
+	Profiles       []Profile `json:"profiles"`
+}
+
+type ExtendRequest struct {
+	Seconds int `json:"seconds"`
+}
+
+const maxExtendSeconds = 60 * 60
+
 func HandleHome(w http.ResponseWriter, r *http.Request) {
 	tmpl, err := template.New("index").Parse(indexHTML)
 	if err != nil {
@@ -90,41 +140,164 @@ func HandleStartTimer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := pomodoro.ValidateDurations(req.FocusDuration, req.BreakDuration, req.RepeatCount); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if status, err := timerManager.prepareTimerRequest(&req); err != nil {
+		http.Error(w, err.Error(), status)
 		return
 	}
 
-	// Stop any existing timer (without writing response)
-	timerManager.mu.Lock()
-	if timerManager.session != nil && timerManager.session.Active {
-		timerManager.session.Active = false
-		select {
-		case timerManager.stopChan <- true:
-		default:
-		}
+	timerManager.startTimerSession(req)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "started"})
+}
+
+// HandleUpdateTimer changes the running session's task without
+// restarting it.
+func HandleUpdateTimer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
-	timerManager.mu.Unlock()
 
-	go timerManager.startTimerSession(req)
+	var req UpdateTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Task == nil {
+		http.Error(w, "Nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	task := strings.TrimSpace(*req.Task)
+	if err := pomodoro.ValidateTask(task); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	setTask := func(timer *pomodoro.Timer) {
+		timer.SetTask(task)
+	}
+	if !timerManager.controlTimerSession(setTask) {
+		http.Error(w, "No active timer", http.StatusConflict)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "started"})
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
 }
 
 func HandleStopTimer(w http.ResponseWriter, r *http.Request) {
-	timerManager.mu.Lock()
-	if timerManager.session != nil && timerManager.session.Active {
-		timerManager.session.Active = false
-		select {
-		case timerManager.stopChan <- true:
-		default:
+	timerManager.stopTimerSession()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "stopped"})
+}
+
+func HandlePauseTimer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !timerManager.controlTimerSession((*pomodoro.Timer).Pause) {
+		http.Error(w, "No active timer", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "paused"})
+}
+
+func HandleResumeTimer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !timerManager.controlTimerSession((*pomodoro.Timer).Resume) {
+		http.Error(w, "No active timer", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "resumed"})
+}
+
+func HandleSkipPhase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !timerManager.controlTimerSession((*pomodoro.Timer).Skip) {
+		http.Error(w, "No active timer", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "skipped"})
+}
+
+func HandleExtendPhase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ExtendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Seconds <= 0 || req.Seconds > maxExtendSeconds {
+		http.Error(w, fmt.Sprintf("Extension must be between 1 and %d seconds", maxExtendSeconds), http.StatusBadRequest)
+		return
+	}
+
+	extend := func(timer *pomodoro.Timer) {
+		timer.Extend(time.Duration(req.Seconds) * time.Second)
+	}
+	if !timerManager.controlTimerSession(extend) {
+		http.Error(w, "No active timer", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "extended"})
+}
+
+// HandleProfiles lists the config file's profiles with every setting filled
+// in, so the page can load one into the form.
+func HandleProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	timerManager.mu.RLock()
+	cfg := timerManager.config
+	timerManager.mu.RUnlock()
+
+	response := ProfilesResponse{Profiles: []Profile{}}
+	if cfg != nil {
+		response.DefaultProfile = cfg.DefaultProfile
+		for _, name := range cfg.ProfileNames() {
+			settings, err := cfg.Resolve(name)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			response.Profiles = append(response.Profiles, Profile{
+				Name:         name,
+				TimerRequest: newTimerRequest(config.Defaults().Merge(settings)),
+			})
 		}
 	}
-	timerManager.mu.Unlock()
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "stopped"})
+	json.NewEncoder(w).Encode(response)
 }
 
 func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
@@ -156,90 +329,251 @@ func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func newTimerRequest(settings config.Settings) TimerRequest {
+	return TimerRequest{
+		FocusDuration:     *settings.Focus,
+		BreakDuration:     *settings.Break,
+		LongBreakDuration: *settings.LongBreak,
+		LongBreakEvery:    *settings.LongBreakEvery,
+		RepeatCount:       *settings.Repeat,
+		ContinueOnBreak:   *settings.Continue,
+	}
+}
+
+func (req TimerRequest) timerConfig() pomodoro.Config {
+	return pomodoro.Config{
+		FocusDuration:     time.Duration(req.FocusDuration) * time.Minute,
+		BreakDuration:     time.Duration(req.BreakDuration) * time.Minute,
+		LongBreakDuration: time.Duration(req.LongBreakDuration) * time.Minute,
+		LongBreakEvery:    req.LongBreakEvery,
+		RepeatCount:       req.RepeatCount,
+		ContinueOnBreak:   req.ContinueOnBreak,
+		Task:              req.Task,
+	}
+}
+
+// prepareTimerRequest validates req and fills in the task's title when it
+// is bound to one, returning the HTTP status to reject it with.
+func (tm *WebTimerManager) prepareTimerRequest(req *TimerRequest) (int, error) {
+	if err := pomodoro.ValidateDurations(req.FocusDuration, req.BreakDuration, req.RepeatCount); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := pomodoro.ValidateLongBreak(req.LongBreakDuration, req.LongBreakEvery); err != nil {
+		return http.StatusBadRequest, err
+	}
+	req.Task = strings.TrimSpace(req.Task)
+	if err := pomodoro.ValidateTask(req.Task); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if req.TaskID != 0 {
+		task, status, err := tm.openTask(req.TaskID)
+		if err != nil {
+			return status, err
+		}
+		if req.Task == "" {
+			req.Task = task.Title
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// startTimerSession replaces any running timer with a new one for req and
+// runs it in the background.
 func (tm *WebTimerManager) startTimerSession(req TimerRequest) {
-	tm.stopChan = make(chan bool, 1)
+	config := req.timerConfig()
+	config.Clock = tm.clock
+	timer := pomodoro.NewTimer(config)
 
 	tm.mu.Lock()
-	tm.session = &TimerSession{
-		Active:       true,
-		Type:         "focus",
-		Duration:     req.FocusDuration,
-		Remaining:    req.FocusDuration * 60,
-		RepeatCount:  req.RepeatCount,
-		CurrentCycle: 1,
+	if tm.timer != nil {
+		tm.timer.Stop()
 	}
+	tm.timer = timer
+	tm.taskID = req.TaskID
+	tm.session = newTimerSession(timer.State())
+	tm.session.TaskID = req.TaskID
 	tm.mu.Unlock()
 
-	for cycle := 1; cycle <= req.RepeatCount; cycle++ {
-		tm.mu.Lock()
-		tm.session.Type = "focus"
-		tm.session.Duration = req.FocusDuration
-		tm.session.Remaining = req.FocusDuration * 60
-		tm.session.CurrentCycle = cycle
-		tm.mu.Unlock()
+	tm.running.Add(1)
+	go tm.runTimer(timer, req.TaskID)
+}
 
-		if !tm.runTimer(req.FocusDuration * 60) {
-			return
-		}
+// stopTimerSession stops the running timer and forgets its session, so
+// the next one starts from idle. Clients are sent the stopped session
+// one last time; the timer's own stop event is stale by then.
+func (tm *WebTimerManager) stopTimerSession() {
+	tm.mu.Lock()
+	timer, stopped := tm.timer, tm.session
+	tm.timer, tm.session, tm.taskID = nil, nil, 0
+	tm.mu.Unlock()
 
-		// Play soft sound when focus period completes
-		go sound.SoftFocusComplete()
+	if timer != nil {
+		timer.Stop()
+	}
+	if stopped != nil {
+		session := *stopped
+		session.Active = false
+		session.EndsAt = nil
+		tm.broadcast(session)
+	}
+}
 
-		if cycle < req.RepeatCount || req.ContinueOnBreak {
-			tm.mu.Lock()
-			tm.session.Type = "break"
-			tm.session.Duration = req.BreakDuration
-			tm.session.Remaining = req.BreakDuration * 60
-			tm.mu.Unlock()
+// waitForTimers waits up to timeout for every timer's goroutine to return,
+// having recorded its last phase, and reports whether they all did.
+func (tm *WebTimerManager) waitForTimers(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		tm.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
 
-			if !tm.runTimer(req.BreakDuration * 60) {
-				return
-			}
+// activeTimer returns the running timer, or nil when no session is active.
+func (tm *WebTimerManager) activeTimer() *pomodoro.Timer {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
 
-			// Play soft sound when break period completes
-			go sound.SoftBreakComplete()
-		}
+	if tm.session == nil || !tm.session.Active {
+		return nil
 	}
+	return tm.timer
+}
 
-	tm.mu.Lock()
-	tm.session.Active = false
-	tm.session.Type = "completed"
-	tm.session.Remaining = 0
-	tm.mu.Unlock()
-	tm.broadcastUpdate()
+// controlTimerSession applies control to the running timer and reports
+// whether there was one. The lock is not held while control runs, since
+// the timer reports the change back through handleTimerEvent.
+func (tm *WebTimerManager) controlTimerSession(control func(*pomodoro.Timer)) bool {
+	timer := tm.activeTimer()
+	if timer == nil {
+		return false
+	}
+	control(timer)
+	return true
 }
 
-func (tm *WebTimerManager) runTimer(durationSeconds int) bool {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+func (tm *WebTimerManager) runTimer(timer *pomodoro.Timer, taskID int) {
+	defer tm.running.Done()
+	var tracker pomodoro.PhaseTracker
+	timer.Run(context.Background(), func(event pomodoro.Event) {
+		if record, ok := tracker.Track(event); ok {
+			tm.recordPhase(record, taskID)
+		}
+		tm.handleTimerEvent(timer, event)
+	})
+}
 
-	for i := durationSeconds; i > 0; i-- {
-		tm.mu.Lock()
-		if tm.session != nil {
-			tm.session.Remaining = i
+// recordPhase appends a finished phase to the history and counts a
+// completed pomodoro towards the session's task, even for a timer that a
+// newer session has replaced.
+func (tm *WebTimerManager) recordPhase(record pomodoro.PhaseRecord, taskID int) {
+	tm.mu.RLock()
+	historyStore, taskStore := tm.history, tm.tasks
+	tm.mu.RUnlock()
+
+	if err := historyStore.Append(record.HistoryEntry()); err != nil {
+		log.Printf("History error: %v", err)
+	}
+	if taskStore != nil && taskID != 0 && record.CompletedPomodoro() {
+		if _, err := taskStore.AddPomodoro(taskID); err != nil {
+			log.Printf("Task error: %v", err)
 		}
+	}
+}
+
+func (tm *WebTimerManager) handleTimerEvent(timer *pomodoro.Timer, event pomodoro.Event) {
+	tm.mu.Lock()
+	if tm.timer != timer {
+		// A newer session replaced this timer; its events are stale.
 		tm.mu.Unlock()
-		tm.broadcastUpdate()
+		return
+	}
+	switch event.Type {
+	case pomodoro.EventStopped:
+		tm.session.Active = false
+		tm.session.EndsAt = nil
+	case pomodoro.EventCompleted:
+		tm.session = newTimerSession(event.State)
+		tm.session.Active = false
+	default:
+		tm.session = newTimerSession(event.State)
+	}
+	tm.session.TaskID = tm.taskID
+	tm.mu.Unlock()
 
-		select {
-		case <-tm.stopChan:
-			return false
-		case <-ticker.C:
+	if event.Type == pomodoro.EventPhaseEnd {
+		tm.mu.RLock()
+		cues := tm.sounds
+		tm.mu.RUnlock()
+		var theme sound.Theme
+		switch event.State.Phase {
+		case pomodoro.PhaseFocus:
+			theme = cues.Focus
+		case pomodoro.PhaseBreak:
+			theme = cues.Break
+		case pomodoro.PhaseLongBreak:
+			theme = cues.LongBreak
 		}
+		go func() {
+			if err := theme.Play(); err != nil {
+				log.Printf("Sound error: %v", err)
+			}
+		}()
 	}
 
-	return true
+	tm.broadcastUpdate()
+}
+
+func newTimerSession(state pomodoro.State) *TimerSession {
+	session := &TimerSession{
+		Active:          true,
+		Type:            string(state.Phase),
+		Duration:        int(state.Duration / time.Minute),
+		DurationSeconds: int(state.Duration / time.Second),
+		Remaining:       int(state.Remaining.Round(time.Second) / time.Second),
+		RepeatCount:     state.RepeatCount,
+		CurrentCycle:    state.Cycle,
+		Paused:          state.Paused,
+		Task:            state.Task,
+	}
+	if !state.EndsAt.IsZero() {
+		endsAt := state.EndsAt
+		session.EndsAt = &endsAt
+	}
+	return session
+}
+
+// closeClients disconnects every WebSocket client so the server can shut
+// down without waiting on their read loops.
+func (tm *WebTimerManager) closeClients() {
+	tm.clientsMu.Lock()
+	defer tm.clientsMu.Unlock()
+
+	for client := range tm.clients {
+		client.Close()
+		delete(tm.clients, client)
+	}
 }
 
 func (tm *WebTimerManager) broadcastUpdate() {
 	tm.mu.RLock()
-	session := tm.session
-	tm.mu.RUnlock()
-
-	if session == nil {
+	if tm.session == nil {
+		tm.mu.RUnlock()
 		return
 	}
+	session := *tm.session
+	tm.mu.RUnlock()
+	tm.broadcast(session)
+}
 
+// broadcast sends session to every WebSocket client, dropping those that
+// cannot be written to.
+func (tm *WebTimerManager) broadcast(session TimerSession) {
 	tm.clientsMu.Lock()
 	defer tm.clientsMu.Unlock()
 
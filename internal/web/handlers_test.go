@@ -5,10 +5,19 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+	"github.com/aureliomalheiros/aragomodoro/internal/config"
+	"github.com/aureliomalheiros/aragomodoro/internal/control"
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
 	"github.com/gorilla/websocket"
 )
 
@@ -52,8 +61,7 @@ func TestHandleHome(t *testing.T) {
 func TestHandleStartTimer(t *testing.T) {
 	// Reset timer manager for test
 	timerManager = &WebTimerManager{
-		clients:  make(map[*websocket.Conn]bool),
-		stopChan: make(chan bool, 1),
+		clients: make(map[*websocket.Conn]bool),
 	}
 
 	tests := []struct {
@@ -86,6 +94,12 @@ func TestHandleStartTimer(t *testing.T) {
 			body:           `{"focusDuration":0,"breakDuration":5,"repeatCount":1,"continueOnBreak":false}`,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "InvalidLongBreak",
+			method:         "POST",
+			body:           `{"focusDuration":25,"breakDuration":5,"longBreakDuration":90,"longBreakEvery":4,"repeatCount":4}`,
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,17 +131,7 @@ func TestHandleStartTimer(t *testing.T) {
 
 				// Give some time for goroutine to start, then stop safely
 				time.Sleep(50 * time.Millisecond)
-				timerManager.mu.Lock()
-				if timerManager.session != nil && timerManager.session.Active {
-					timerManager.session.Active = false
-				}
-				timerManager.mu.Unlock()
-
-				// Signal stop to cleanup goroutine
-				select {
-				case timerManager.stopChan <- true:
-				default:
-				}
+				timerManager.stopTimerSession()
 			}
 		})
 	}
@@ -157,6 +161,102 @@ func TestHandleStopTimer(t *testing.T) {
 	}
 }
 
+func TestHandleUpdateTimer(t *testing.T) {
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+	}
+
+	rr := httptest.NewRecorder()
+	HandleUpdateTimer(rr, httptest.NewRequest("PATCH", "/api/timer", strings.NewReader(`{"task":"review"}`)))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d without a session, got %d", http.StatusConflict, rr.Code)
+	}
+
+	timerManager.startTimerSession(TimerRequest{FocusDuration: 25, BreakDuration: 5, RepeatCount: 1, Task: "write RFC"})
+	defer timerManager.stopTimerSession()
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+	}{
+		{"WrongMethod", "POST", `{"task":"review"}`, http.StatusMethodNotAllowed},
+		{"NoFields", "PATCH", `{}`, http.StatusBadRequest},
+		{"InvalidJSON", "PATCH", `{"task":}`, http.StatusBadRequest},
+		{"TooLong", "PATCH", `{"task":"` + strings.Repeat("a", 201) + `"}`, http.StatusBadRequest},
+		{"Valid", "PATCH", `{"task":"  review RFC  "}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HandleUpdateTimer(rr, httptest.NewRequest(tt.method, "/api/timer", strings.NewReader(tt.body)))
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+
+	timerManager.mu.RLock()
+	task := timerManager.session.Task
+	timerManager.mu.RUnlock()
+	if task != "review RFC" {
+		t.Errorf("Expected task %q, got %q", "review RFC", task)
+	}
+}
+
+func TestHandleProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "break: 7\ndefault_profile: study\nprofiles:\n  study:\n    focus: 40\n  deep-work:\n    focus: 50\n    repeat: 4\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewServer(8080).SetConfig(cfg)
+	defer NewServer(8080).SetConfig(nil)
+
+	req := httptest.NewRequest("GET", "/api/profiles", nil)
+	rr := httptest.NewRecorder()
+	HandleProfiles(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	var response ProfilesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response JSON: %v", err)
+	}
+	expected := ProfilesResponse{
+		DefaultProfile: "study",
+		Profiles: []Profile{
+			{Name: "deep-work", TimerRequest: TimerRequest{FocusDuration: 50, BreakDuration: 7, LongBreakDuration: 15, LongBreakEvery: 4, RepeatCount: 4}},
+			{Name: "study", TimerRequest: TimerRequest{FocusDuration: 40, BreakDuration: 7, LongBreakDuration: 15, LongBreakEvery: 4, RepeatCount: 1}},
+		},
+	}
+	if !reflect.DeepEqual(response, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, response)
+	}
+}
+
+func TestHandleProfiles_NoConfig(t *testing.T) {
+	rr := httptest.NewRecorder()
+	HandleProfiles(rr, httptest.NewRequest("GET", "/api/profiles", nil))
+
+	if strings.TrimSpace(rr.Body.String()) != `{"profiles":[]}` {
+		t.Errorf("Expected an empty profile list, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	HandleProfiles(rr, httptest.NewRequest("POST", "/api/profiles", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rr.Code)
+	}
+}
+
 func TestTimerSession(t *testing.T) {
 	session := &TimerSession{
 		Active:       true,
@@ -181,8 +281,7 @@ func TestTimerSession(t *testing.T) {
 func TestWebTimerManager(t *testing.T) {
 	// Create a new timer manager for this test to avoid conflicts
 	testManager := &WebTimerManager{
-		clients:  make(map[*websocket.Conn]bool),
-		stopChan: make(chan bool, 1),
+		clients: make(map[*websocket.Conn]bool),
 	}
 
 	req := TimerRequest{
@@ -234,8 +333,7 @@ func TestWebTimerManager(t *testing.T) {
 func TestServerRoutes(t *testing.T) {
 	// Reset timer manager for test
 	timerManager = &WebTimerManager{
-		clients:  make(map[*websocket.Conn]bool),
-		stopChan: make(chan bool, 1),
+		clients: make(map[*websocket.Conn]bool),
 	}
 
 	server := NewServer(8080)
@@ -246,6 +344,8 @@ func TestServerRoutes(t *testing.T) {
 	}{
 		{"/", "GET"},
 		{"/api/timer/stop", "POST"},
+		{"/api/tasks", "GET"},
+		{"/api/tasks/1", "GET"},
 		{"/ws", "GET"}, // WebSocket endpoint
 	}
 
@@ -310,3 +410,280 @@ type stringReadCloser struct {
 func (src *stringReadCloser) Close() error {
 	return nil
 }
+
+func TestHandlePauseResumeTimer(t *testing.T) {
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+	}
+
+	// Without a running session there is nothing to pause
+	rr := httptest.NewRecorder()
+	HandlePauseTimer(rr, httptest.NewRequest("POST", "/api/timer/pause", nil))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d without a session, got %d", http.StatusConflict, rr.Code)
+	}
+
+	timerManager.startTimerSession(TimerRequest{FocusDuration: 25, BreakDuration: 5, RepeatCount: 1})
+	defer timerManager.stopTimerSession()
+
+	steps := []struct {
+		handler http.HandlerFunc
+		path    string
+		status  string
+		paused  bool
+	}{
+		{HandlePauseTimer, "/api/timer/pause", "paused", true},
+		{HandleResumeTimer, "/api/timer/resume", "resumed", false},
+	}
+
+	for _, step := range steps {
+		t.Run(step.status, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			step.handler(rr, httptest.NewRequest("POST", step.path, nil))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			var response map[string]string
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response JSON: %v", err)
+			}
+			if response["status"] != step.status {
+				t.Errorf("Expected status '%s', got '%s'", step.status, response["status"])
+			}
+
+			timerManager.mu.RLock()
+			paused := timerManager.session.Paused
+			timerManager.mu.RUnlock()
+			if paused != step.paused {
+				t.Errorf("Expected session paused=%v, got %v", step.paused, paused)
+			}
+		})
+	}
+
+	rr = httptest.NewRecorder()
+	HandlePauseTimer(rr, httptest.NewRequest("GET", "/api/timer/pause", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d for GET, got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+}
+
+func TestHandleSkipAndExtendPhase(t *testing.T) {
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+	}
+
+	rr := httptest.NewRecorder()
+	HandleSkipPhase(rr, httptest.NewRequest("POST", "/api/timer/skip", nil))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected status %d without a session, got %d", http.StatusConflict, rr.Code)
+	}
+
+	timerManager.startTimerSession(TimerRequest{FocusDuration: 25, BreakDuration: 5, RepeatCount: 2})
+	defer timerManager.stopTimerSession()
+
+	extendTests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"Valid", `{"seconds":300}`, http.StatusOK},
+		{"Zero", `{"seconds":0}`, http.StatusBadRequest},
+		{"TooLong", `{"seconds":3601}`, http.StatusBadRequest},
+		{"InvalidJSON", `{"seconds":}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range extendTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HandleExtendPhase(rr, httptest.NewRequest("POST", "/api/timer/extend", strings.NewReader(tt.body)))
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+
+	timerManager.mu.RLock()
+	durationSeconds := timerManager.session.DurationSeconds
+	timerManager.mu.RUnlock()
+	if durationSeconds != 30*60 {
+		t.Errorf("Expected extended duration of %d seconds, got %d", 30*60, durationSeconds)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleSkipPhase(rr, httptest.NewRequest("POST", "/api/timer/skip", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		timerManager.mu.RLock()
+		sessionType := timerManager.session.Type
+		timerManager.mu.RUnlock()
+		if sessionType == "break" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected session to move to break after skip, still %q", sessionType)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// advanceFake moves a fake clock one second at a time, waiting for the
+// running timer to arm its next tick before each step.
+func advanceFake(fake *clock.Fake, seconds int) {
+	for i := 0; i < seconds; i++ {
+		fake.BlockUntil(1)
+		fake.Advance(time.Second)
+	}
+}
+
+// waitForSession polls the manager until its session satisfies match.
+func waitForSession(t *testing.T, tm *WebTimerManager, match func(TimerSession) bool) TimerSession {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tm.mu.RLock()
+		var session TimerSession
+		if tm.session != nil {
+			session = *tm.session
+		}
+		tm.mu.RUnlock()
+
+		if match(session) {
+			return session
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for session, last state: %+v", session)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWebTimerManager_FullSession(t *testing.T) {
+	// Phase ends play sounds on their own goroutines, which may still be
+	// running after the test, so the recorder stays in place from here on.
+	recorder := &sound.Recorder{}
+	sound.SetOutput(recorder)
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	testManager := &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		clock:   fake,
+		sounds:  sound.WebCues(),
+	}
+
+	testManager.startTimerSession(TimerRequest{FocusDuration: 1, BreakDuration: 1, RepeatCount: 2})
+
+	steps := []struct {
+		phase string
+		cycle int
+	}{
+		{"break", 1},
+		{"focus", 2},
+		{"break", 2},
+	}
+	for _, step := range steps {
+		advanceFake(fake, 60)
+		session := waitForSession(t, testManager, func(s TimerSession) bool {
+			return s.Type == step.phase && s.CurrentCycle == step.cycle
+		})
+		if !session.Active || session.Remaining != 60 {
+			t.Errorf("Expected an active %s with 60s left, got %+v", step.phase, session)
+		}
+		if expected := fake.Now().Add(time.Minute); session.EndsAt == nil || !session.EndsAt.Equal(expected) {
+			t.Errorf("Expected %s to end at %v, got %v", step.phase, expected, session.EndsAt)
+		}
+	}
+
+	advanceFake(fake, 60)
+	session := waitForSession(t, testManager, func(s TimerSession) bool {
+		return s.Type == "completed"
+	})
+	if session.Active {
+		t.Error("Completed session should not be active")
+	}
+
+	var played []string
+	for deadline := time.Now().Add(5 * time.Second); len(played) < 4 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		played = played[:0]
+		for _, theme := range recorder.Played() {
+			played = append(played, theme.Name)
+		}
+	}
+	// Each plays on a goroutine of its own, so the order is not certain.
+	sort.Strings(played)
+	if expected := []string{"soft-break", "soft-break", "soft-focus", "soft-focus"}; strings.Join(played, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v played, got %v", expected, played)
+	}
+}
+
+func TestWebTimerManager_StopMidPhase(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := clock.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	testManager := &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		clock:   fake,
+		history: store,
+	}
+
+	previous := timerManager
+	timerManager = testManager
+	defer func() { timerManager = previous }()
+	server := httptest.NewServer(http.HandlerFunc(HandleWebSocket))
+	defer server.Close()
+
+	testManager.startTimerSession(TimerRequest{FocusDuration: 1, BreakDuration: 1, RepeatCount: 1})
+	advanceFake(fake, 30)
+	waitForSession(t, testManager, func(s TimerSession) bool {
+		return s.Remaining == 30
+	})
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The page is sent the stopped session, while the manager goes back to
+	// idle for the next one.
+	testManager.stopTimerSession()
+	if status := testManager.status(); status != (control.Status{}) {
+		t.Errorf("Expected no session after stop, got %+v", status)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var session TimerSession
+	for session.Type == "" || session.Active {
+		session = TimerSession{}
+		if err := conn.ReadJSON(&session); err != nil {
+			t.Fatalf("Expected the stopped session sent, got %v", err)
+		}
+	}
+	if session.Type != "focus" || session.Remaining != 30 {
+		t.Errorf("Expected focus stopped with 30s left, got %+v", session)
+	}
+	if session.EndsAt != nil {
+		t.Errorf("A stopped session should have no end time, got %v", session.EndsAt)
+	}
+
+	// The timer reports the stop after the session is gone, and it is
+	// recorded by the time waitForTimers returns.
+	if !testManager.waitForTimers(5 * time.Second) {
+		t.Fatal("Expected the stopped timer's goroutine to return")
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected the stopped phase in the history, got %+v", entries)
+	}
+	if entry := entries[0]; entry.Type != "focus" || entry.Outcome != history.OutcomeStopped || entry.Actual != 30*time.Second || entry.Planned != time.Minute {
+		t.Errorf("Unexpected history entry %+v", entry)
+	}
+}
@@ -1,10 +1,22 @@
 package web
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/config"
+	"github.com/aureliomalheiros/aragomodoro/internal/history"
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/aureliomalheiros/aragomodoro/internal/tasks"
 )
 
+// shutdownTimeout bounds how long in-flight requests get to finish.
+const shutdownTimeout = 5 * time.Second
+
 type Server struct {
 	port int
 	mux  *http.ServeMux
@@ -20,15 +32,92 @@ func NewServer(port int) *Server {
 	return s
 }
 
+// SetHistory makes timer sessions started through the server append their
+// phases to store.
+func (s *Server) SetHistory(store *history.Store) {
+	timerManager.mu.Lock()
+	timerManager.history = store
+	timerManager.mu.Unlock()
+}
+
+// SetConfig makes the config file's profiles available to the page.
+func (s *Server) SetConfig(cfg *config.Config) {
+	timerManager.mu.Lock()
+	timerManager.config = cfg
+	timerManager.mu.Unlock()
+}
+
+// SetTasks makes the task list available to the page and lets sessions
+// count pomodoros towards a task.
+func (s *Server) SetTasks(store *tasks.Store) {
+	timerManager.mu.Lock()
+	timerManager.tasks = store
+	timerManager.mu.Unlock()
+}
+
+// SetSounds picks the themes played as phases end.
+func (s *Server) SetSounds(cues sound.Cues) {
+	timerManager.mu.Lock()
+	timerManager.sounds = cues
+	timerManager.mu.Unlock()
+}
+
 func (s *Server) Start() error {
+	return s.Run(context.Background())
+}
+
+// Run serves until ctx is done, then stops any running timer session, closes
+// the WebSocket clients and shuts the server down gracefully.
+func (s *Server) Run(ctx context.Context) error {
 	addr := fmt.Sprintf(":%d", s.port)
-	fmt.Printf("Server listening on %s\n", addr)
-	return http.ListenAndServe(addr, s.mux)
+	server := &http.Server{Addr: addr, Handler: s.mux}
+	server.RegisterOnShutdown(timerManager.closeClients)
+
+	errc := make(chan error, 1)
+	go func() {
+		fmt.Printf("Server listening on %s\n", addr)
+		errc <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.StopSession()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// StopSession stops the running timer session, if any, and waits up to
+// shutdownTimeout for its stopped phase to reach the history and task list.
+func (s *Server) StopSession() {
+	timerManager.stopTimerSession()
+	if !timerManager.waitForTimers(shutdownTimeout) {
+		log.Printf("Timer did not stop within %v; its last phase may not be recorded", shutdownTimeout)
+	}
 }
 
 func (s *Server) setupRoutes() {
 	s.mux.HandleFunc("/", HandleHome)
+	s.mux.HandleFunc("/api/timer", HandleUpdateTimer)
 	s.mux.HandleFunc("/api/timer/start", HandleStartTimer)
 	s.mux.HandleFunc("/api/timer/stop", HandleStopTimer)
+	s.mux.HandleFunc("/api/timer/pause", HandlePauseTimer)
+	s.mux.HandleFunc("/api/timer/resume", HandleResumeTimer)
+	s.mux.HandleFunc("/api/timer/skip", HandleSkipPhase)
+	s.mux.HandleFunc("/api/timer/extend", HandleExtendPhase)
+	s.mux.HandleFunc("/api/profiles", HandleProfiles)
+	s.mux.HandleFunc("/api/tasks", HandleTasks)
+	s.mux.HandleFunc("/api/tasks/", HandleTask)
 	s.mux.HandleFunc("/ws", HandleWebSocket)
 }
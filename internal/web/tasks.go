@@ -0,0 +1,184 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/tasks"
+)
+
+type TaskRequest struct {
+	Title    string `json:"title"`
+	Estimate int    `json:"estimate"`
+}
+
+// UpdateTaskRequest changes a task; fields left out stay as they are.
+type UpdateTaskRequest struct {
+	Title    *string `json:"title"`
+	Estimate *int    `json:"estimate"`
+	Done     *bool   `json:"done"`
+}
+
+// HandleTasks lists the task list on GET and adds a task on POST.
+func HandleTasks(w http.ResponseWriter, r *http.Request) {
+	store := timerManager.taskStore()
+	if store == nil {
+		http.Error(w, "Task list not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := store.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []tasks.Task{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		var req TaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if req.Estimate == 0 {
+			req.Estimate = 1
+		}
+		if err := validateTask(req.Title, req.Estimate); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		task, err := store.Add(req.Title, req.Estimate)
+		if err != nil {
+			http.Error(w, err.Error(), taskErrorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(task)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleTask reads, changes or deletes the task at /api/tasks/{id}.
+func HandleTask(w http.ResponseWriter, r *http.Request) {
+	store := timerManager.taskStore()
+	if store == nil {
+		http.Error(w, "Task list not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/tasks/"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid task id", http.StatusNotFound)
+		return
+	}
+
+	var task tasks.Task
+	switch r.Method {
+	case http.MethodGet:
+		task, err = store.Get(id)
+	case http.MethodPatch:
+		var req UpdateTaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := validateUpdate(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		task, err = updateTask(store, id, req)
+	case http.MethodDelete:
+		if err := store.Delete(id); err != nil {
+			http.Error(w, err.Error(), taskErrorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), taskErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func validateTask(title string, estimate int) error {
+	if _, err := tasks.ValidateTitle(title); err != nil {
+		return err
+	}
+	return tasks.ValidateEstimate(estimate)
+}
+
+func validateUpdate(req UpdateTaskRequest) error {
+	if req.Title != nil {
+		if _, err := tasks.ValidateTitle(*req.Title); err != nil {
+			return err
+		}
+	}
+	if req.Estimate != nil {
+		return tasks.ValidateEstimate(*req.Estimate)
+	}
+	return nil
+}
+
+func updateTask(store *tasks.Store, id int, req UpdateTaskRequest) (tasks.Task, error) {
+	task, err := store.Update(id, func(task *tasks.Task) error {
+		if req.Title != nil {
+			task.Title, _ = tasks.ValidateTitle(*req.Title)
+		}
+		if req.Estimate != nil {
+			task.Estimate = *req.Estimate
+		}
+		return nil
+	})
+	if err == nil && req.Done != nil && *req.Done != task.Done {
+		task, err = store.SetDone(id, *req.Done)
+	}
+	return task, err
+}
+
+func taskErrorStatus(err error) int {
+	if errors.Is(err, tasks.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func (tm *WebTimerManager) taskStore() *tasks.Store {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.tasks
+}
+
+// openTask looks up a task a session is about to be bound to, returning
+// the HTTP status to report when it cannot be.
+func (tm *WebTimerManager) openTask(id int) (tasks.Task, int, error) {
+	store := tm.taskStore()
+	if store == nil {
+		return tasks.Task{}, http.StatusServiceUnavailable, errors.New("Task list not available")
+	}
+	task, err := store.Get(id)
+	if err != nil {
+		return tasks.Task{}, taskErrorStatus(err), err
+	}
+	if task.Done {
+		return tasks.Task{}, http.StatusBadRequest, errors.New("Task is already done")
+	}
+	return task, http.StatusOK, nil
+}
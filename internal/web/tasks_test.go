@@ -0,0 +1,251 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aureliomalheiros/aragomodoro/internal/clock"
+	"github.com/aureliomalheiros/aragomodoro/internal/sound"
+	"github.com/aureliomalheiros/aragomodoro/internal/tasks"
+	"github.com/gorilla/websocket"
+)
+
+func newTaskStore(t *testing.T) *tasks.Store {
+	t.Helper()
+	store, err := tasks.Open(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func serveTasks(handler http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandleTasks(t *testing.T) {
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		tasks:   newTaskStore(t),
+	}
+
+	rr := serveTasks(HandleTasks, "GET", "/api/tasks", "")
+	if rr.Code != http.StatusOK || rr.Body.String() != "[]\n" {
+		t.Fatalf("Expected an empty list, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	rr = serveTasks(HandleTasks, "POST", "/api/tasks", `{"title":"  Write report ","estimate":3}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var task tasks.Task
+	if err := json.Unmarshal(rr.Body.Bytes(), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != 1 || task.Title != "Write report" || task.Estimate != 3 {
+		t.Errorf("Unexpected task %+v", task)
+	}
+
+	rr = serveTasks(HandleTasks, "POST", "/api/tasks", `{"title":"Review"}`)
+	if err := json.Unmarshal(rr.Body.Bytes(), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Estimate != 1 {
+		t.Errorf("Expected the estimate to default to 1, got %d", task.Estimate)
+	}
+
+	rr = serveTasks(HandleTasks, "GET", "/api/tasks", "")
+	var list []tasks.Task
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Errorf("Expected 2 tasks, got %+v", list)
+	}
+
+	invalid := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{"EmptyTitle", "POST", `{"title":"  "}`, http.StatusBadRequest},
+		{"BadEstimate", "POST", `{"title":"Plan","estimate":-2}`, http.StatusBadRequest},
+		{"InvalidJSON", "POST", `{`, http.StatusBadRequest},
+		{"InvalidMethod", "DELETE", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := serveTasks(HandleTasks, tt.method, "/api/tasks", tt.body); rr.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, rr.Code)
+			}
+		})
+	}
+}
+
+func TestHandleTask(t *testing.T) {
+	store := newTaskStore(t)
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		tasks:   store,
+	}
+	if _, err := store.Add("Write report", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	decode := func(rr *httptest.ResponseRecorder) tasks.Task {
+		t.Helper()
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var task tasks.Task
+		if err := json.Unmarshal(rr.Body.Bytes(), &task); err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+
+	if task := decode(serveTasks(HandleTask, "GET", "/api/tasks/1", "")); task.Title != "Write report" {
+		t.Errorf("Unexpected task %+v", task)
+	}
+
+	task := decode(serveTasks(HandleTask, "PATCH", "/api/tasks/1", `{"title":"Write the report","estimate":4}`))
+	if task.Title != "Write the report" || task.Estimate != 4 || task.Done {
+		t.Errorf("Unexpected task after update %+v", task)
+	}
+
+	task = decode(serveTasks(HandleTask, "PATCH", "/api/tasks/1", `{"done":true}`))
+	if !task.Done || task.DoneAt == nil || task.Estimate != 4 {
+		t.Errorf("Expected the task done, got %+v", task)
+	}
+
+	if rr := serveTasks(HandleTask, "PATCH", "/api/tasks/1", `{"estimate":0}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad estimate, got %d", rr.Code)
+	}
+	if task, _ := store.Get(1); task.Estimate != 4 {
+		t.Errorf("A rejected update should change nothing, got %+v", task)
+	}
+
+	if rr := serveTasks(HandleTask, "DELETE", "/api/tasks/1", ""); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200 on delete, got %d", rr.Code)
+	}
+
+	notFound := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/tasks/1"},
+		{"PATCH", "/api/tasks/1"},
+		{"DELETE", "/api/tasks/1"},
+		{"GET", "/api/tasks/abc"},
+		{"GET", "/api/tasks/0"},
+	}
+	for _, tt := range notFound {
+		if rr := serveTasks(HandleTask, tt.method, tt.path, `{}`); rr.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected 404, got %d", tt.method, tt.path, rr.Code)
+		}
+	}
+}
+
+func TestHandleTasks_NoStore(t *testing.T) {
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+	}
+
+	if rr := serveTasks(HandleTasks, "GET", "/api/tasks", ""); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rr.Code)
+	}
+	if rr := serveTasks(HandleTask, "GET", "/api/tasks/1", ""); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rr.Code)
+	}
+}
+
+func TestHandleStartTimer_Task(t *testing.T) {
+	store := newTaskStore(t)
+	timerManager = &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		tasks:   store,
+	}
+	if _, err := store.Add("Write report", 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add("Old chore", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetDone(2, true); err != nil {
+		t.Fatal(err)
+	}
+
+	rejected := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"UnknownTask", `{"focusDuration":25,"breakDuration":5,"repeatCount":1,"taskId":9}`, http.StatusNotFound},
+		{"DoneTask", `{"focusDuration":25,"breakDuration":5,"repeatCount":1,"taskId":2}`, http.StatusBadRequest},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := serveTasks(HandleStartTimer, "POST", "/api/timer/start", tt.body); rr.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, rr.Code)
+			}
+		})
+	}
+
+	rr := serveTasks(HandleStartTimer, "POST", "/api/timer/start", `{"focusDuration":25,"breakDuration":5,"repeatCount":1,"taskId":1}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	defer timerManager.stopTimerSession()
+
+	session := waitForSession(t, timerManager, func(s TimerSession) bool {
+		return s.Active
+	})
+	if session.TaskID != 1 || session.Task != "Write report" {
+		t.Errorf("Expected the session bound to task #1, got %+v", session)
+	}
+}
+
+func TestWebTimerManager_TaskPomodoros(t *testing.T) {
+	sound.SetOutput(sound.Null{})
+
+	store := newTaskStore(t)
+	if _, err := store.Add("Write report", 2); err != nil {
+		t.Fatal(err)
+	}
+	fake := clock.NewFake(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	testManager := &WebTimerManager{
+		clients: make(map[*websocket.Conn]bool),
+		clock:   fake,
+		tasks:   store,
+	}
+
+	testManager.startTimerSession(TimerRequest{FocusDuration: 1, BreakDuration: 1, RepeatCount: 2, TaskID: 1})
+	advanceFake(fake, 60)
+	waitForSession(t, testManager, func(s TimerSession) bool {
+		return s.Type == "break"
+	})
+	testManager.stopTimerSession()
+
+	// The pomodoro is counted once the finished phase has been recorded.
+	var task tasks.Task
+	for deadline := time.Now().Add(5 * time.Second); task.Pomodoros == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		var err error
+		if task, err = store.Get(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if task.Pomodoros != 1 {
+		t.Errorf("Expected 1 pomodoro counted for the task, got %+v", task)
+	}
+}
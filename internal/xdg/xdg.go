@@ -0,0 +1,68 @@
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DataDir is the aragomodoro directory under $XDG_DATA_HOME, or under
+// ~/.local/share when that is not set.
+func DataDir() (string, error) {
+	return appDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// ConfigDir is the aragomodoro directory under $XDG_CONFIG_HOME, or under
+// ~/.config when that is not set.
+func ConfigDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// RuntimeDir is the aragomodoro directory under $XDG_RUNTIME_DIR, for
+// sockets and other files that only live as long as the session. Without
+// it, a per-user directory in the system temp directory stands in.
+func RuntimeDir() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" || !filepath.IsAbs(dir) {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("aragomodoro-%d", os.Getuid()))
+	}
+	return filepath.Join(dir, "aragomodoro")
+}
+
+// MkdirPrivate creates dir, and its parents, unless it exists. It then
+// makes sure dir is fit for sockets and logs: a real directory, not a
+// symlink, owned by the current user and usable by nobody else. Without
+// XDG_RUNTIME_DIR the runtime directory sits in the shared temp directory,
+// where another user could have created it first.
+func MkdirPrivate(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return CheckPrivate(dir)
+}
+
+// CheckPrivate fails unless dir is a real directory owned by the current
+// user and usable by nobody else.
+func CheckPrivate(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return checkOwner(dir, info)
+}
+
+// appDir follows the XDG spec in ignoring a relative path in env.
+func appDir(env, fallback string) (string, error) {
+	dir := os.Getenv(env)
+	if dir == "" || !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locating home directory: %w", err)
+		}
+		dir = filepath.Join(home, fallback)
+	}
+	return filepath.Join(dir, "aragomodoro"), nil
+}
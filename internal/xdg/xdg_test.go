@@ -0,0 +1,78 @@
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirs(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		value    string
+		dir      func() (string, error)
+		expected string
+	}{
+		{"DataFromEnv", "XDG_DATA_HOME", "/tmp/data", DataDir, "/tmp/data/aragomodoro"},
+		{"DataFallback", "XDG_DATA_HOME", "", DataDir, "/home/frodo/.local/share/aragomodoro"},
+		{"DataRelativeIgnored", "XDG_DATA_HOME", "data", DataDir, "/home/frodo/.local/share/aragomodoro"},
+		{"ConfigFromEnv", "XDG_CONFIG_HOME", "/tmp/config", ConfigDir, "/tmp/config/aragomodoro"},
+		{"ConfigFallback", "XDG_CONFIG_HOME", "", ConfigDir, "/home/frodo/.config/aragomodoro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/frodo")
+			t.Setenv(tt.env, tt.value)
+
+			dir, err := tt.dir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dir != filepath.FromSlash(tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, dir)
+			}
+		})
+	}
+}
+
+func TestRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if dir := RuntimeDir(); dir != filepath.FromSlash("/run/user/1000/aragomodoro") {
+		t.Errorf("Expected the runtime dir from the environment, got %q", dir)
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	expected := filepath.Join(os.TempDir(), fmt.Sprintf("aragomodoro-%d", os.Getuid()))
+	if dir := RuntimeDir(); dir != expected {
+		t.Errorf("Expected %q without XDG_RUNTIME_DIR, got %q", expected, dir)
+	}
+}
+
+func TestMkdirPrivate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "run", "aragomodoro")
+	if err := MkdirPrivate(dir); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("Expected a 0700 directory, got %v, %v", info, err)
+	}
+	if err := MkdirPrivate(dir); err != nil {
+		t.Errorf("Expected an existing private directory accepted, got %v", err)
+	}
+
+	open := filepath.Join(t.TempDir(), "open")
+	os.Mkdir(open, 0o700)
+	os.Chmod(open, 0o777)
+	link := filepath.Join(t.TempDir(), "link")
+	os.Symlink(dir, link)
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, nil, 0o600)
+	for _, path := range []string{open, link, file} {
+		if err := MkdirPrivate(path); err == nil {
+			t.Errorf("MkdirPrivate(%s) should refuse it", path)
+		}
+	}
+}